@@ -18,7 +18,7 @@ func main() {
 	notificationService := service.NewNotificationService(emailService)
 
 	brokers := []string{"localhost:9092"}
-	topics := []string{"order-events"}
+	topics := []string{"order-events", "wishlist-events", "product-events"}
 
 	consumer := kafka.NewConsumer(brokers, topics, notificationService)
 
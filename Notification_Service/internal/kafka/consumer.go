@@ -10,6 +10,14 @@ import (
 	"github.com/IBM/sarama"
 )
 
+const productEventsTopic = "product-events"
+
+// События product-events, о которых Notification Service сообщает поставщику
+var moderationEvents = map[string]bool{
+	"product_approved": true,
+	"product_rejected": true,
+}
+
 type Consumer struct {
 	brokers []string
 	topics  []string
@@ -19,6 +27,9 @@ type Consumer struct {
 type NotificationHandler interface {
 	HandleOrderEvent(event models.OrderEvent) error
 	HandlePaymentEvent(event models.PaymentEvent) error
+	HandleWishlistEvent(event models.WishlistEvent) error
+	HandleProductEvent(event models.ProductEvent) error
+	HandleQuestionEvent(event models.QuestionEvent) error
 }
 
 func NewConsumer(brokers []string, topics []string, handler NotificationHandler) *Consumer {
@@ -83,24 +94,30 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 				return nil
 			}
 
-			log.Printf("Received message from topic %s: %s", message.Topic, string(message.Value))
-
 			var messageData map[string]interface{}
 			if err := json.Unmarshal(message.Value, &messageData); err != nil {
-				log.Printf("Error unmarshaling message: %v", err)
+				log.Printf("Error unmarshaling message from topic %s: %v", message.Topic, err)
 				session.MarkMessage(message, "")
 				continue
 			}
 
 			eventType, ok := messageData["event_type"].(string)
 			if !ok {
-				log.Printf("No event_type found in message")
+				log.Printf("No event_type found in message from topic %s", message.Topic)
 				session.MarkMessage(message, "")
 				continue
 			}
 
+			// В product-events публикуются все изменения каталога, письма нужны только о решениях модерации
+			if message.Topic == productEventsTopic && !moderationEvents[eventType] {
+				session.MarkMessage(message, "")
+				continue
+			}
+
+			log.Printf("Received message from topic %s: %s", message.Topic, string(message.Value))
+
 			switch eventType {
-			case "order_created", "order_status_updated":
+			case "order_created", "order_status_updated", "order_cancelled", "order_payment_reminder":
 				var orderEvent models.OrderEvent
 				if err := json.Unmarshal(message.Value, &orderEvent); err == nil {
 					if err := h.handler.HandleOrderEvent(orderEvent); err != nil {
@@ -119,6 +136,33 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 				} else {
 					log.Printf("Error unmarshaling PaymentEvent: %v", err)
 				}
+			case "wishlist_price_drop", "wishlist_back_in_stock":
+				var wishlistEvent models.WishlistEvent
+				if err := json.Unmarshal(message.Value, &wishlistEvent); err == nil {
+					if err := h.handler.HandleWishlistEvent(wishlistEvent); err != nil {
+						log.Printf("Error handling wishlist event: %v", err)
+					}
+				} else {
+					log.Printf("Error unmarshaling WishlistEvent: %v", err)
+				}
+			case "product_approved", "product_rejected":
+				var productEvent models.ProductEvent
+				if err := json.Unmarshal(message.Value, &productEvent); err == nil {
+					if err := h.handler.HandleProductEvent(productEvent); err != nil {
+						log.Printf("Error handling product event: %v", err)
+					}
+				} else {
+					log.Printf("Error unmarshaling ProductEvent: %v", err)
+				}
+			case "question_asked", "question_answered":
+				var questionEvent models.QuestionEvent
+				if err := json.Unmarshal(message.Value, &questionEvent); err == nil {
+					if err := h.handler.HandleQuestionEvent(questionEvent); err != nil {
+						log.Printf("Error handling question event: %v", err)
+					}
+				} else {
+					log.Printf("Error unmarshaling QuestionEvent: %v", err)
+				}
 			default:
 				log.Printf("Unknown event type: %s", eventType)
 			}
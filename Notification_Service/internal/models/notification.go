@@ -17,15 +17,27 @@ const (
 )
 
 type OrderEvent struct {
-	EventType   string    `json:"event_type"`
-	OrderID     int       `json:"order_id"`
-	ProductName string    `json:"product_name"`
-	ProductID   int       `json:"product_id"`
-	SupplierID  int       `json:"supplier_id"`
-	ClientID    int       `json:"client_id"`
-	Amount      float64   `json:"amount"`
-	Status      string    `json:"status,omitempty"`
-	Timestamp   time.Time `json:"timestamp"`
+	EventType       string      `json:"event_type"`
+	OrderID         int         `json:"order_id"`
+	ProductName     string      `json:"product_name"`
+	ProductID       int         `json:"product_id"`
+	SupplierID      int         `json:"supplier_id"`
+	ClientID        int         `json:"client_id"`
+	Amount          Money       `json:"amount"`
+	Status          string      `json:"status,omitempty"`
+	Items           []OrderItem `json:"items,omitempty"`
+	Reason          string      `json:"reason,omitempty"`
+	PaymentDeadline *time.Time  `json:"payment_deadline,omitempty"`
+	Timestamp       time.Time   `json:"timestamp"`
+}
+
+type OrderItem struct {
+	ProductID   int    `json:"product_id"`
+	VariantID   int    `json:"variant_id,omitempty"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   Money  `json:"unit_price"`
+	LineTotal   Money  `json:"line_total"`
 }
 
 type UserInfo struct {
@@ -7,7 +7,7 @@ type PaymentEvent struct {
 	PaymentID string    `json:"payment_id"`
 	OrderID   int       `json:"order_id"`
 	ClientID  int       `json:"client_id"`
-	Amount    float64   `json:"amount"`
+	Amount    Money     `json:"amount"`
 	Status    string    `json:"status"`
 	Timestamp time.Time `json:"timestamp"`
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ProductEvent — событие из product-events; Notification Service использует только решения модерации.
+type ProductEvent struct {
+	EventType  string `json:"event_type"`
+	ProductID  int    `json:"product_id"`
+	SupplierID int    `json:"supplier_id"`
+	Product    struct {
+		Name string `json:"name"`
+	} `json:"product"`
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type QuestionEvent struct {
+	EventType   string    `json:"event_type"`
+	QuestionID  int       `json:"question_id"`
+	ProductID   int       `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	SupplierID  int       `json:"supplier_id"`
+	ClientID    int       `json:"client_id"`
+	Question    string    `json:"question"`
+	Answer      string    `json:"answer,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type WishlistEvent struct {
+	EventType   string    `json:"event_type"`
+	ClientID    int       `json:"client_id"`
+	ProductID   int       `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	OldPrice    *Money    `json:"old_price,omitempty"`
+	Price       Money     `json:"price"`
+	Timestamp   time.Time `json:"timestamp"`
+}
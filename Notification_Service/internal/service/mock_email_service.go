@@ -4,6 +4,7 @@ import (
 	"Notification_Service/internal/models"
 	"fmt"
 	"log"
+	"strings"
 )
 
 type MockEmailService struct {
@@ -32,16 +33,32 @@ func (e *MockEmailService) CreateOrderNotificationEmail(orderEvent models.OrderE
 	case "order_created":
 		if userInfo.Role == "supplier" {
 			subject = fmt.Sprintf("Новый заказ #%d", orderEvent.OrderID)
-			body = "У вас новый заказ: " + orderEvent.ProductName
+			body = "У вас новый заказ:\n\n" + orderItemsText(orderEvent)
 		} else {
 			subject = fmt.Sprintf("Заказ #%d оформлен", orderEvent.OrderID)
-			body = "Ваш заказ на " + orderEvent.ProductName + " успешно оформлен"
+			body = "Ваш заказ успешно оформлен:\n\n" + orderItemsText(orderEvent)
 		}
 
 	case "order_status_updated":
 		subject = fmt.Sprintf("Обновление статуса заказа #%d", orderEvent.OrderID)
 		body = "Статус заказа изменился на: " + orderEvent.Status
 
+	case "order_payment_reminder":
+		subject = fmt.Sprintf("Оплатите заказ #%d", orderEvent.OrderID)
+		body = fmt.Sprintf("Уважаемый %s,\n\nЗаказ #%d на сумму %s еще не оплачен.", userInfo.Username, orderEvent.OrderID, orderEvent.Amount)
+		if orderEvent.PaymentDeadline != nil {
+			body += fmt.Sprintf(" Если оплата не поступит до %s, заказ будет отменен автоматически.",
+				orderEvent.PaymentDeadline.Local().Format("02.01.2006 15:04"))
+		}
+
+	case "order_cancelled":
+		subject = fmt.Sprintf("Заказ #%d отменен", orderEvent.OrderID)
+		if orderEvent.Reason == "payment_timeout" {
+			body = fmt.Sprintf("Заказ #%d отменен автоматически: оплата не поступила вовремя.", orderEvent.OrderID)
+		} else {
+			body = fmt.Sprintf("Заказ #%d отменен.", orderEvent.OrderID)
+		}
+
 	default:
 		subject = fmt.Sprintf("Уведомление о заказе #%d", orderEvent.OrderID)
 		body = "Информация о заказе обновлена"
@@ -55,6 +72,21 @@ func (e *MockEmailService) CreateOrderNotificationEmail(orderEvent models.OrderE
 	}
 }
 
+// orderItemsText перечисляет позиции заказа построчно и добавляет итоговую сумму.
+// События без items (от старых версий Order Service) описываются одной строкой с названием товара.
+func orderItemsText(orderEvent models.OrderEvent) string {
+	if len(orderEvent.Items) == 0 {
+		return fmt.Sprintf("%s — %s", orderEvent.ProductName, orderEvent.Amount)
+	}
+
+	var b strings.Builder
+	for _, item := range orderEvent.Items {
+		fmt.Fprintf(&b, "%s × %d = %s\n", item.ProductName, item.Quantity, item.LineTotal)
+	}
+	fmt.Fprintf(&b, "\nИтого: %s", orderEvent.Amount)
+	return b.String()
+}
+
 func (m *MockEmailService) CreatePaymentCompletedNotificationEmail(orderEvent models.OrderEvent, userInfo models.UserInfo) models.EmailNotification {
 	subject := fmt.Sprintf("Оплата заказа #%d успешно завершена", orderEvent.OrderID)
 	body := fmt.Sprintf("Уважаемый %s,\n\nВаш заказ #%d успешно оплачен!\n\nСпасибо за покупку!",
@@ -70,7 +102,7 @@ func (m *MockEmailService) CreatePaymentCompletedNotificationEmail(orderEvent mo
 
 func (m *MockEmailService) CreatePaymentRequiredNotificationEmail(orderEvent models.OrderEvent, userInfo models.UserInfo) models.EmailNotification {
 	subject := fmt.Sprintf("Требуется оплата заказа #%d", orderEvent.OrderID)
-	body := fmt.Sprintf("Уважаемый %s,\n\nДля заказа #%d требуется оплата в размере %.2f руб.\n\nПожалуйста, перейдите к оплате.",
+	body := fmt.Sprintf("Уважаемый %s,\n\nДля заказа #%d требуется оплата в размере %s.\n\nПожалуйста, перейдите к оплате.",
 		userInfo.Username, orderEvent.OrderID, orderEvent.Amount)
 
 	return models.EmailNotification{
@@ -80,3 +112,89 @@ func (m *MockEmailService) CreatePaymentRequiredNotificationEmail(orderEvent mod
 		HTML:    false,
 	}
 }
+
+func (m *MockEmailService) CreateWishlistNotificationEmail(event models.WishlistEvent, userInfo models.UserInfo) models.EmailNotification {
+	var subject, body string
+
+	switch event.EventType {
+	case "wishlist_price_drop":
+		subject = fmt.Sprintf("%s подешевел", event.ProductName)
+		if event.OldPrice != nil {
+			body = fmt.Sprintf("Уважаемый %s,\n\nЦена на %s из вашего списка желаний снизилась: было %s, стало %s.",
+				userInfo.Username, event.ProductName, event.OldPrice, event.Price)
+		} else {
+			body = fmt.Sprintf("Уважаемый %s,\n\nЦена на %s из вашего списка желаний снизилась до %s.",
+				userInfo.Username, event.ProductName, event.Price)
+		}
+
+	case "wishlist_back_in_stock":
+		subject = fmt.Sprintf("%s снова в наличии", event.ProductName)
+		body = fmt.Sprintf("Уважаемый %s,\n\n%s из вашего списка желаний снова в продаже по цене %s.",
+			userInfo.Username, event.ProductName, event.Price)
+
+	default:
+		subject = "Обновление списка желаний"
+		body = "Товар из вашего списка желаний изменился: " + event.ProductName
+	}
+
+	return models.EmailNotification{
+		To:      userInfo.Email,
+		Subject: subject,
+		Body:    body,
+		HTML:    false,
+	}
+}
+
+func (m *MockEmailService) CreateModerationNotificationEmail(event models.ProductEvent, userInfo models.UserInfo) models.EmailNotification {
+	var subject, body string
+
+	switch event.EventType {
+	case "product_approved":
+		subject = fmt.Sprintf("Товар «%s» опубликован", event.Product.Name)
+		body = fmt.Sprintf("Уважаемый %s,\n\nВаш товар «%s» прошел модерацию и теперь виден покупателям.",
+			userInfo.Username, event.Product.Name)
+
+	case "product_rejected":
+		subject = fmt.Sprintf("Товар «%s» отклонен", event.Product.Name)
+		body = fmt.Sprintf("Уважаемый %s,\n\nВаш товар «%s» не прошел модерацию.\n\nПричина: %s\n\nИсправьте товар и отправьте его на проверку повторно.",
+			userInfo.Username, event.Product.Name, event.Reason)
+
+	default:
+		subject = "Обновление статуса товара"
+		body = "Статус вашего товара изменился: " + event.Product.Name
+	}
+
+	return models.EmailNotification{
+		To:      userInfo.Email,
+		Subject: subject,
+		Body:    body,
+		HTML:    false,
+	}
+}
+
+func (m *MockEmailService) CreateQuestionNotificationEmail(event models.QuestionEvent, userInfo models.UserInfo) models.EmailNotification {
+	var subject, body string
+
+	switch event.EventType {
+	case "question_asked":
+		subject = fmt.Sprintf("Новый вопрос о товаре «%s»", event.ProductName)
+		body = fmt.Sprintf("Уважаемый %s,\n\nПокупатель задал вопрос о вашем товаре «%s»:\n\n%s\n\nОтветьте на него в личном кабинете — ответ увидят все покупатели.",
+			userInfo.Username, event.ProductName, event.Question)
+
+	case "question_answered":
+		subject = fmt.Sprintf("Ответ на ваш вопрос о товаре «%s»", event.ProductName)
+		body = fmt.Sprintf("Уважаемый %s,\n\nПоставщик ответил на ваш вопрос о товаре «%s».\n\nВопрос: %s\n\nОтвет: %s",
+			userInfo.Username, event.ProductName, event.Question, event.Answer)
+
+	default:
+		subject = "Вопросы о товаре"
+		body = "Обновление в вопросах о товаре " + event.ProductName
+	}
+
+	return models.EmailNotification{
+		To:      userInfo.Email,
+		Subject: subject,
+		Body:    body,
+		HTML:    false,
+	}
+}
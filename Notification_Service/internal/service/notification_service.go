@@ -16,6 +16,9 @@ type EmailServiceInterface interface {
 	CreateOrderNotificationEmail(orderEvent models.OrderEvent, userInfo models.UserInfo) models.EmailNotification
 	CreatePaymentRequiredNotificationEmail(orderEvent models.OrderEvent, userInfo models.UserInfo) models.EmailNotification
 	CreatePaymentCompletedNotificationEmail(orderEvent models.OrderEvent, userInfo models.UserInfo) models.EmailNotification
+	CreateWishlistNotificationEmail(event models.WishlistEvent, userInfo models.UserInfo) models.EmailNotification
+	CreateModerationNotificationEmail(event models.ProductEvent, userInfo models.UserInfo) models.EmailNotification
+	CreateQuestionNotificationEmail(event models.QuestionEvent, userInfo models.UserInfo) models.EmailNotification
 }
 
 type NotificationService struct {
@@ -33,7 +36,7 @@ func (ns *NotificationService) HandleOrderEvent(event models.OrderEvent) error {
 
 	switch event.EventType {
 	case "order_created":
-		if err := ns.sendOrderCreatedNotificationToSupplier(event); err != nil {
+		if err := ns.sendOrderNotificationToSupplier(event); err != nil {
 			log.Printf("Failed to send notification to supplier: %v", err)
 		}
 
@@ -41,9 +44,18 @@ func (ns *NotificationService) HandleOrderEvent(event models.OrderEvent) error {
 			log.Printf("Failed to send notification to client: %v", err)
 		}
 
-	case "order_status_updated":
+	case "order_status_updated", "order_payment_reminder":
 		if err := ns.sendOrderStatusUpdateNotificationToClient(event); err != nil {
-			log.Printf("Failed to send status update notification to client: %v", err)
+			log.Printf("Failed to send %s notification to client: %v", event.EventType, err)
+		}
+
+	case "order_cancelled":
+		if err := ns.sendOrderStatusUpdateNotificationToClient(event); err != nil {
+			log.Printf("Failed to send cancellation notification to client: %v", err)
+		}
+
+		if err := ns.sendOrderNotificationToSupplier(event); err != nil {
+			log.Printf("Failed to send cancellation notification to supplier: %v", err)
 		}
 
 	case "payment_completed":
@@ -58,7 +70,7 @@ func (ns *NotificationService) HandleOrderEvent(event models.OrderEvent) error {
 	return nil
 }
 
-func (ns *NotificationService) sendOrderCreatedNotificationToSupplier(event models.OrderEvent) error {
+func (ns *NotificationService) sendOrderNotificationToSupplier(event models.OrderEvent) error {
 	supplierInfo, err := ns.getUserInfo(event.SupplierID)
 	if err != nil {
 		return fmt.Errorf("failed to get supplier info: %w", err)
@@ -242,3 +254,60 @@ func (ns *NotificationService) sendPaymentRequiredNotificationToClient(event mod
 	log.Printf("Payment required notification sent to %s for order %d", userInfo.Email, event.OrderID)
 	return nil
 }
+
+func (ns *NotificationService) HandleWishlistEvent(event models.WishlistEvent) error {
+	log.Printf("Processing wishlist event: %+v", event)
+
+	userInfo, err := ns.getUserInfo(event.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	notification := ns.emailService.CreateWishlistNotificationEmail(event, *userInfo)
+	if err := ns.emailService.SendEmail(notification); err != nil {
+		return fmt.Errorf("failed to send wishlist email: %w", err)
+	}
+
+	log.Printf("Wishlist notification %s sent to %s for product %d", event.EventType, userInfo.Email, event.ProductID)
+	return nil
+}
+
+func (ns *NotificationService) HandleProductEvent(event models.ProductEvent) error {
+	log.Printf("Processing product event: %+v", event)
+
+	userInfo, err := ns.getUserInfo(event.SupplierID)
+	if err != nil {
+		return fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	notification := ns.emailService.CreateModerationNotificationEmail(event, *userInfo)
+	if err := ns.emailService.SendEmail(notification); err != nil {
+		return fmt.Errorf("failed to send moderation email: %w", err)
+	}
+
+	log.Printf("Moderation notification %s sent to %s for product %d", event.EventType, userInfo.Email, event.ProductID)
+	return nil
+}
+
+// HandleQuestionEvent уведомляет поставщика о новом вопросе к товару, а клиента — об ответе на его вопрос.
+func (ns *NotificationService) HandleQuestionEvent(event models.QuestionEvent) error {
+	log.Printf("Processing question event: %+v", event)
+
+	recipientID := event.SupplierID
+	if event.EventType == "question_answered" {
+		recipientID = event.ClientID
+	}
+
+	userInfo, err := ns.getUserInfo(recipientID)
+	if err != nil {
+		return fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	notification := ns.emailService.CreateQuestionNotificationEmail(event, *userInfo)
+	if err := ns.emailService.SendEmail(notification); err != nil {
+		return fmt.Errorf("failed to send question email: %w", err)
+	}
+
+	log.Printf("Question notification %s sent to %s for question %d", event.EventType, userInfo.Email, event.QuestionID)
+	return nil
+}
@@ -3,8 +3,16 @@ package main
 import (
 	"Order_Service/internal/api"
 	"Order_Service/internal/kafka"
+	"Order_Service/internal/products"
 	"Order_Service/internal/repository"
+	"Order_Service/internal/service"
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -15,14 +23,87 @@ func main() {
 		log.Fatal(err)
 	}
 
-	kafkaProducer, err := kafka.NewProducer([]string{"localhost:9092"})
+	brokers := []string{"localhost:9092"}
+
+	kafkaProducer, err := kafka.NewProducer(brokers)
 	if err != nil {
 		log.Printf("Failed to create Kafka producer: %v", err)
 	}
 
-	api := api.NewAPI(mux.NewRouter(), db, kafkaProducer)
+	// Заказы переходят в paid или payment_failed по событиям Payment Service
+	paymentService := service.NewPaymentService(db, kafkaProducer)
+	consumer := kafka.NewConsumer(brokers, []string{"order-events"}, paymentService)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		if err := consumer.Start(ctx); err != nil {
+			log.Fatalf("Error starting Kafka consumer: %v", err)
+		}
+	}()
+
+	// По умолчанию неоплаченный заказ отменяется через сутки, за два часа до срока клиенту напоминают об оплате
+	unpaidOrderPolicy, err := unpaidOrderPolicyFromEnv(service.UnpaidOrderPolicy{
+		PaymentWindow:  24 * time.Hour,
+		ReminderBefore: 2 * time.Hour,
+		CheckInterval:  time.Minute,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	unpaidOrderCanceller, err := service.NewUnpaidOrderCanceller(db, kafkaProducer, unpaidOrderPolicy)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go unpaidOrderCanceller.Start(ctx)
+
+	productClient := products.NewClient("http://localhost:8082")
+
+	api := api.NewAPI(mux.NewRouter(), db, kafkaProducer, productClient)
 	api.Handle()
 
-	log.Println("Order Service started on :8084")
-	log.Fatal(api.ListenAndServe("localhost:8084"))
+	go func() {
+		log.Println("Order Service started on :8084")
+		if err := api.ListenAndServe("localhost:8084"); err != nil {
+			log.Fatalf("Error starting HTTP server: %v", err)
+		}
+	}()
+
+	sigterm := make(chan os.Signal, 1)
+	signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
+	<-sigterm
+
+	log.Println("Shutting down Order Service...")
+	cancel()
+	if kafkaProducer != nil {
+		kafkaProducer.Close()
+	}
+	log.Println("Order Service stopped")
+}
+
+// unpaidOrderPolicyFromEnv заменяет значения политики по умолчанию заданными в окружении
+// длительностями вида "24h" или "90m". Пустая переменная оставляет значение по умолчанию.
+func unpaidOrderPolicyFromEnv(policy service.UnpaidOrderPolicy) (service.UnpaidOrderPolicy, error) {
+	settings := []struct {
+		env   string
+		value *time.Duration
+	}{
+		{"ORDER_PAYMENT_WINDOW", &policy.PaymentWindow},
+		{"ORDER_PAYMENT_REMINDER_BEFORE", &policy.ReminderBefore},
+		{"ORDER_UNPAID_CHECK_INTERVAL", &policy.CheckInterval},
+	}
+
+	for _, setting := range settings {
+		value := os.Getenv(setting.env)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return policy, fmt.Errorf("invalid %s: %w", setting.env, err)
+		}
+		*setting.value = duration
+	}
+	return policy, nil
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"Order_Service/internal/kafka"
+	"Order_Service/internal/products"
 	"Order_Service/internal/repository"
 	"net/http"
 
@@ -12,10 +13,11 @@ type api struct {
 	r        *mux.Router
 	db       *repository.PGRepo
 	producer *kafka.Producer
+	products *products.Client
 }
 
-func NewAPI(r *mux.Router, db *repository.PGRepo, producer *kafka.Producer) *api {
-	return &api{r: r, db: db, producer: producer}
+func NewAPI(r *mux.Router, db *repository.PGRepo, producer *kafka.Producer, products *products.Client) *api {
+	return &api{r: r, db: db, producer: producer, products: products}
 }
 
 func (api *api) Handle() {
@@ -24,6 +26,14 @@ func (api *api) Handle() {
 	api.r.HandleFunc("/api/order/client", api.GetAllOrdersForClientHandler)
 	api.r.HandleFunc("/api/order/delete", api.DeleteOrderHandler).Queries("id", "{id}")
 	api.r.HandleFunc("/api/order/status/{id}", api.UpdateOrderStatusHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/order/{id:[0-9]+}/history", api.GetOrderHistoryHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/cart", api.GetCartHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/cart", api.ClearCartHandler).Methods(http.MethodDelete)
+	api.r.HandleFunc("/api/cart/items", api.AddCartItemHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/cart/items", api.UpdateCartItemHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/cart/items/{product_id:[0-9]+}", api.DeleteCartItemHandler).Methods(http.MethodDelete)
+	api.r.HandleFunc("/api/cart/merge", api.MergeCartHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/cart/checkout", api.CheckoutHandler).Methods(http.MethodPost)
 }
 
 func (api *api) ListenAndServe(addr string) error {
@@ -0,0 +1,506 @@
+package api
+
+import (
+	"Order_Service/internal/models"
+	"Order_Service/internal/products"
+	"Order_Service/internal/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Заголовок, в котором гость передает токен своей анонимной корзины
+const cartTokenHeader = "X-Cart-Token"
+
+func (api *api) GetCartHandler(w http.ResponseWriter, r *http.Request) {
+	cart, ok := api.cartFromRequest(w, r, false)
+	if !ok {
+		return
+	}
+
+	api.writeCart(w, r, cart, http.StatusOK)
+}
+
+func (api *api) AddCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	var request models.CartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.Quantity == 0 {
+		request.Quantity = 1
+	}
+	if request.ProductID <= 0 {
+		http.Error(w, "Product id is required", http.StatusBadRequest)
+		return
+	}
+	if request.Quantity < 0 || request.Quantity > maxOrderQuantity {
+		http.Error(w, fmt.Sprintf("Quantity must be between 1 and %d", maxOrderQuantity), http.StatusBadRequest)
+		return
+	}
+
+	cart, ok := api.cartFromRequest(w, r, true)
+	if !ok {
+		return
+	}
+
+	if !cartContains(cart, request.ProductID, request.VariantID) && len(cart.Items) >= maxOrderItems {
+		http.Error(w, fmt.Sprintf("Cart can contain at most %d items", maxOrderItems), http.StatusBadRequest)
+		return
+	}
+
+	// В корзину нельзя положить несуществующий товар или вариант; остаток проверяется при просмотре и оформлении
+	product, err := api.products.GetProduct(request.ProductID, r.Header.Get("Authorization"))
+	if errors.Is(err, products.ErrProductNotFound) {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get product %d: %v", request.ProductID, err)
+		http.Error(w, "Product service unavailable", http.StatusBadGateway)
+		return
+	}
+
+	switch _, _, problem := productPrice(product, request.VariantID); problem {
+	case models.CartProblemVariantNotFound:
+		http.Error(w, "Variant not found", http.StatusNotFound)
+		return
+	case models.CartProblemVariantRequired:
+		http.Error(w, "Variant id is required for this product", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.db.AddCartItem(cart.ID, request, maxOrderQuantity); err != nil {
+		http.Error(w, "Error adding item to cart", http.StatusInternalServerError)
+		return
+	}
+
+	if cart, ok = api.reloadCart(w, cart); !ok {
+		return
+	}
+	api.writeCart(w, r, cart, http.StatusOK)
+}
+
+// UpdateCartItemHandler задает количество позиции; количество 0 убирает ее из корзины.
+func (api *api) UpdateCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	var request models.CartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.Quantity < 0 || request.Quantity > maxOrderQuantity {
+		http.Error(w, fmt.Sprintf("Quantity must be between 0 and %d", maxOrderQuantity), http.StatusBadRequest)
+		return
+	}
+
+	cart, ok := api.cartFromRequest(w, r, false)
+	if !ok {
+		return
+	}
+
+	var err error
+	if request.Quantity == 0 {
+		err = api.db.DeleteCartItem(cart.ID, request.ProductID, request.VariantID)
+	} else {
+		err = api.db.SetCartItemQuantity(cart.ID, request)
+	}
+	if errors.Is(err, repository.ErrCartItemNotFound) {
+		http.Error(w, "Cart item not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating cart", http.StatusInternalServerError)
+		return
+	}
+
+	if cart, ok = api.reloadCart(w, cart); !ok {
+		return
+	}
+	api.writeCart(w, r, cart, http.StatusOK)
+}
+
+func (api *api) DeleteCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(mux.Vars(r)["product_id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	variantID := 0
+	if value := r.URL.Query().Get("variant_id"); value != "" {
+		variantID, err = strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Invalid variant id", http.StatusBadRequest)
+			return
+		}
+	}
+
+	cart, ok := api.cartFromRequest(w, r, false)
+	if !ok {
+		return
+	}
+
+	err = api.db.DeleteCartItem(cart.ID, productID, variantID)
+	if errors.Is(err, repository.ErrCartItemNotFound) {
+		http.Error(w, "Cart item not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating cart", http.StatusInternalServerError)
+		return
+	}
+
+	if cart, ok = api.reloadCart(w, cart); !ok {
+		return
+	}
+	api.writeCart(w, r, cart, http.StatusOK)
+}
+
+func (api *api) ClearCartHandler(w http.ResponseWriter, r *http.Request) {
+	cart, ok := api.cartFromRequest(w, r, false)
+	if !ok {
+		return
+	}
+
+	if cart.ID != 0 {
+		if err := api.db.ClearCart(cart.ID); err != nil {
+			http.Error(w, "Error clearing cart", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MergeCartHandler переносит анонимную корзину из X-Cart-Token в корзину клиента после входа.
+func (api *api) MergeCartHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "client" {
+		http.Error(w, "Only clients can use a cart", http.StatusForbidden)
+		return
+	}
+
+	token := r.Header.Get(cartTokenHeader)
+	if token == "" {
+		http.Error(w, cartTokenHeader+" header is required", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.MergeGuestCart(token, user.ID, maxOrderItems, maxOrderQuantity)
+	if errors.Is(err, repository.ErrCartNotFound) {
+		http.Error(w, "Cart not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, repository.ErrCartFull) {
+		http.Error(w, fmt.Sprintf("Merged cart would contain more than %d items", maxOrderItems), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error merging carts", http.StatusInternalServerError)
+		return
+	}
+
+	cart, err := api.db.GetClientCart(user.ID)
+	if err != nil {
+		http.Error(w, "Error getting cart", http.StatusInternalServerError)
+		return
+	}
+	api.writeCart(w, r, cart, http.StatusOK)
+}
+
+// CheckoutHandler превращает корзину клиента в заказы — по одному на каждого поставщика.
+// Если позиция недоступна или цена изменилась с последнего просмотра корзины, заказы не создаются:
+// клиент получает 409 с обновленной корзиной и может повторить оформление.
+func (api *api) CheckoutHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "client" {
+		http.Error(w, "Only clients can make an order", http.StatusForbidden)
+		return
+	}
+
+	cart, err := api.db.GetClientCart(user.ID)
+	if err != nil {
+		http.Error(w, "Error getting cart", http.StatusInternalServerError)
+		return
+	}
+
+	if len(cart.Items) == 0 {
+		http.Error(w, "Cart is empty", http.StatusBadRequest)
+		return
+	}
+
+	if !api.refreshCart(w, r, &cart) {
+		return
+	}
+
+	if !cart.CanCheckout || cartPricesChanged(cart) {
+		writeJSON(w, http.StatusConflict, cart)
+		return
+	}
+
+	orders, err := ordersFromCart(cart, user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.CheckoutCart(cart.ID, cart.Items, orders)
+	if errors.Is(err, repository.ErrCartChanged) {
+		http.Error(w, "Cart has changed, review it and try again", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating orders", http.StatusInternalServerError)
+		return
+	}
+
+	if api.producer != nil {
+		for _, order := range orders {
+			orderEvent := models.NewOrderEvent("order_created", order)
+			if err := api.producer.PublishMessage("order-events", orderEvent); err != nil {
+				log.Printf("Failed to publish order created event: %v", err)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, models.CheckoutResponse{Orders: orders})
+}
+
+// cartFromRequest находит корзину покупателя: клиента — по токену авторизации, гостя — по
+// заголовку X-Cart-Token. Если гостевой корзины нет, при create она создается с новым токеном,
+// иначе возвращается пустая несохраненная корзина (ID = 0).
+// При ошибке ответ уже записан в w.
+func (api *api) cartFromRequest(w http.ResponseWriter, r *http.Request, create bool) (models.Cart, bool) {
+	if r.Header.Get("Authorization") != "" {
+		user, err := api.validateUserToken(r)
+		if err != nil {
+			http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+			return models.Cart{}, false
+		}
+
+		if user.Role != "client" {
+			http.Error(w, "Only clients can use a cart", http.StatusForbidden)
+			return models.Cart{}, false
+		}
+
+		cart, err := api.db.GetClientCart(user.ID)
+		if err != nil {
+			http.Error(w, "Error getting cart", http.StatusInternalServerError)
+			return models.Cart{}, false
+		}
+		return cart, true
+	}
+
+	if token := r.Header.Get(cartTokenHeader); token != "" {
+		cart, err := api.db.GetGuestCart(token)
+		if err == nil {
+			return cart, true
+		}
+		if !errors.Is(err, repository.ErrCartNotFound) {
+			http.Error(w, "Error getting cart", http.StatusInternalServerError)
+			return models.Cart{}, false
+		}
+	}
+
+	if !create {
+		return models.Cart{Items: []models.CartItem{}}, true
+	}
+
+	token, err := newCartToken()
+	if err != nil {
+		http.Error(w, "Error creating cart", http.StatusInternalServerError)
+		return models.Cart{}, false
+	}
+
+	cart, err := api.db.CreateGuestCart(token)
+	if err != nil {
+		http.Error(w, "Error creating cart", http.StatusInternalServerError)
+		return models.Cart{}, false
+	}
+	return cart, true
+}
+
+// reloadCart перечитывает позиции корзины после изменения.
+// При ошибке ответ уже записан в w.
+func (api *api) reloadCart(w http.ResponseWriter, cart models.Cart) (models.Cart, bool) {
+	var err error
+	cart.Items, err = api.db.GetCartItems(cart.ID)
+	if err != nil {
+		http.Error(w, "Error getting cart", http.StatusInternalServerError)
+		return models.Cart{}, false
+	}
+	return cart, true
+}
+
+// writeCart обновляет цены и наличие позиций и отдает корзину. Показанные цены запоминаются,
+// чтобы при следующем просмотре или оформлении сообщить об их изменении.
+func (api *api) writeCart(w http.ResponseWriter, r *http.Request, cart models.Cart, status int) {
+	if !api.refreshCart(w, r, &cart) {
+		return
+	}
+
+	if cart.Token != "" {
+		w.Header().Set(cartTokenHeader, cart.Token)
+	}
+	writeJSON(w, status, cart)
+}
+
+// refreshCart сверяет позиции с Product Service: подставляет актуальные название, поставщика,
+// цену и остаток, отмечает недоступные позиции и считает суммы по валютам. Новые цены сохраняются.
+// При ошибке ответ уже записан в w.
+func (api *api) refreshCart(w http.ResponseWriter, r *http.Request, cart *models.Cart) bool {
+	authorization := r.Header.Get("Authorization")
+	fetched := make(map[int]*products.Product)
+	totals := make(map[string]int)
+	cart.Totals = []models.Money{}
+	cart.CanCheckout = len(cart.Items) > 0
+
+	for i := range cart.Items {
+		item := &cart.Items[i]
+
+		product, ok := fetched[item.ProductID]
+		if !ok {
+			found, err := api.products.GetProduct(item.ProductID, authorization)
+			if err != nil && !errors.Is(err, products.ErrProductNotFound) {
+				log.Printf("Failed to get product %d: %v", item.ProductID, err)
+				http.Error(w, "Product service unavailable", http.StatusBadGateway)
+				return false
+			}
+			if err == nil {
+				product = &found
+			}
+			fetched[item.ProductID] = product
+		}
+
+		if product == nil {
+			item.Available, item.Problem = false, models.CartProblemNotFound
+			cart.CanCheckout = false
+			continue
+		}
+
+		item.ProductName = product.Name
+		item.SupplierID = product.UserID
+
+		unitPrice, stock, problem := productPrice(*product, item.VariantID)
+		if problem != "" {
+			item.Available, item.Problem = false, problem
+			cart.CanCheckout = false
+			continue
+		}
+
+		if item.UnitPrice != nil && *item.UnitPrice != unitPrice {
+			previous := *item.UnitPrice
+			item.PreviousPrice = &previous
+		}
+		lineTotal := unitPrice.Multiply(item.Quantity)
+		item.UnitPrice = &unitPrice
+		item.LineTotal = &lineTotal
+		item.Stock = stock
+		item.Available = stock >= item.Quantity
+		if !item.Available {
+			item.Problem = models.CartProblemInsufficientStock
+			cart.CanCheckout = false
+			continue
+		}
+
+		if j, ok := totals[lineTotal.Currency]; ok {
+			cart.Totals[j].Amount += lineTotal.Amount
+		} else {
+			totals[lineTotal.Currency] = len(cart.Totals)
+			cart.Totals = append(cart.Totals, lineTotal)
+		}
+	}
+
+	if cart.ID != 0 {
+		if err := api.db.UpdateCartPrices(cart.ID, cart.Items); err != nil {
+			log.Printf("Failed to save cart %d prices: %v", cart.ID, err)
+		}
+	}
+	return true
+}
+
+// cartPricesChanged сообщает, изменилась ли цена хотя бы одной позиции с последнего просмотра.
+func cartPricesChanged(cart models.Cart) bool {
+	for _, item := range cart.Items {
+		if item.PreviousPrice != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func cartContains(cart models.Cart, productID, variantID int) bool {
+	for _, item := range cart.Items {
+		if item.ProductID == productID && item.VariantID == variantID {
+			return true
+		}
+	}
+	return false
+}
+
+// ordersFromCart группирует проверенные позиции корзины по поставщикам в порядке их появления.
+func ordersFromCart(cart models.Cart, clientID int) ([]models.Order, error) {
+	var supplierIDs []int
+	itemsBySupplier := make(map[int][]models.OrderItem)
+
+	for _, item := range cart.Items {
+		if _, ok := itemsBySupplier[item.SupplierID]; !ok {
+			supplierIDs = append(supplierIDs, item.SupplierID)
+		}
+		itemsBySupplier[item.SupplierID] = append(itemsBySupplier[item.SupplierID], models.OrderItem{
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   *item.UnitPrice,
+			LineTotal:   *item.LineTotal,
+		})
+	}
+
+	orders := make([]models.Order, 0, len(supplierIDs))
+	for _, supplierID := range supplierIDs {
+		order, err := models.NewOrder(supplierID, itemsBySupplier[supplierID])
+		if err != nil {
+			return nil, fmt.Errorf("all items from supplier %d must be in the same currency", supplierID)
+		}
+		order.ClientID = clientID
+		order.Status = models.OrderStatusPending
+		order.PaymentStatus = models.PaymentStatusPending
+		order.PaymentTracked = true
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func newCartToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GetOrderHistoryHandler отдает историю статусов заказа его клиенту и поставщику.
+func (api *api) GetOrderHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	orderID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	order, err := api.db.GetOrderByID(orderID)
+	if err != nil {
+		http.Error(w, "Order not found", http.StatusNotFound)
+		return
+	}
+
+	if (user.Role != "client" || order.ClientID != user.ID) && (user.Role != "supplier" || order.SupplierID != user.ID) {
+		http.Error(w, "You can only view history of your own orders", http.StatusForbidden)
+		return
+	}
+
+	history, err := api.db.GetOrderStatusHistory(orderID)
+	if err != nil {
+		http.Error(w, "Error getting order history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
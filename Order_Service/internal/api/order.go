@@ -3,15 +3,22 @@ package api
 import (
 	"Order_Service/internal/jwt"
 	"Order_Service/internal/models"
+	"Order_Service/internal/repository"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
-	"time"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gorilla/mux"
 )
 
+// Максимальная длина причины смены статуса
+const maxStatusReasonLength = 500
+
 func (api *api) CreateOrderHandler(w http.ResponseWriter, r *http.Request) {
 	user, err := api.validateUserToken(r)
 	if err != nil {
@@ -24,34 +31,32 @@ func (api *api) CreateOrderHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var order models.Order
-	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+	var request models.CreateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	order, ok := api.priceOrder(w, r, request)
+	if !ok {
+		return
+	}
+
 	order.ClientID = user.ID
-	order.Status = "pending"
+	order.Status = models.OrderStatusPending
+	order.PaymentStatus = models.PaymentStatusPending
+	order.PaymentTracked = true
 
 	orderID, err := api.db.CreateOrder(order)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	order.ID = orderID
 
 	// Создали событие о создании заказа
 	if api.producer != nil {
-		orderEvent := models.OrderEvent{
-			EventType:   "order_created",
-			OrderID:     orderID,
-			ProductName: order.ProductName,
-			ProductID:   order.ProductID,
-			SupplierID:  order.SupplierID,
-			ClientID:    order.ClientID,
-			Amount:      order.Amount,
-			Status:      order.Status,
-			Timestamp:   time.Now(),
-		}
+		orderEvent := models.NewOrderEvent("order_created", order)
 
 		// Отправляем его в кафку
 		if err := api.producer.PublishMessage("order-events", orderEvent); err != nil {
@@ -107,6 +112,8 @@ func (api *api) GetAllOrdersForSupplierHandler(w http.ResponseWriter, r *http.Re
 	json.NewEncoder(w).Encode(orders)
 }
 
+// DeleteOrderHandler отменяет заказ клиента. Заказ не удаляется из базы: отмена идет по таблице переходов,
+// записывается в историю и публикуется как order_cancelled, чтобы снять резерв и отменить платеж.
 func (api *api) DeleteOrderHandler(w http.ResponseWriter, r *http.Request) {
 	user, err := api.validateUserToken(r)
 	if err != nil {
@@ -131,11 +138,49 @@ func (api *api) DeleteOrderHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid order id", http.StatusBadRequest)
 		return
 	}
-	err = api.db.DeleteOrderByID(id, user.ID)
+
+	order, err := api.db.GetOrderByID(id)
+	if err != nil || order.ClientID != user.ID {
+		http.Error(w, "Order not found", http.StatusNotFound)
+		return
+	}
+
+	err = order.CheckTransition(models.OrderStatusCancelled, user.Role)
+	switch {
+	case errors.Is(err, models.ErrTransitionForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	err = api.db.UpdateOrderStatus(models.OrderStatusChange{
+		OrderID:   id,
+		OldStatus: order.Status,
+		NewStatus: models.OrderStatusCancelled,
+		ActorID:   user.ID,
+		ActorRole: user.Role,
+	})
+	if errors.Is(err, repository.ErrOrderStatusChanged) {
+		http.Error(w, "Order status has changed, reload the order and try again", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Error deleting order", http.StatusInternalServerError)
 		return
 	}
+
+	if api.producer != nil {
+		order.Status = models.OrderStatusCancelled
+		orderEvent := models.NewOrderEvent("order_cancelled", *order)
+		if err := api.producer.PublishMessage("order-events", orderEvent); err != nil {
+			log.Printf("Failed to publish order cancelled event: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": models.OrderStatusCancelled})
 }
 
 func (api *api) UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request) {
@@ -145,8 +190,8 @@ func (api *api) UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if user.Role != "supplier" {
-		http.Error(w, "Only suppliers can update order status", http.StatusForbidden)
+	if user.Role != "supplier" && user.Role != "client" {
+		http.Error(w, "Only suppliers and clients can update order status", http.StatusForbidden)
 		return
 	}
 
@@ -169,17 +214,14 @@ func (api *api) UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	validStatuses := []string{"pending", "confirmed", "processing", "shipped", "delivered", "cancelled"}
-	isValidStatus := false
-	for _, status := range validStatuses {
-		if updateRequest.Status == status {
-			isValidStatus = true
-			break
-		}
+	if !models.IsValidOrderStatus(updateRequest.Status) {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
 	}
 
-	if !isValidStatus {
-		http.Error(w, "Invalid status", http.StatusBadRequest)
+	updateRequest.Reason = strings.TrimSpace(updateRequest.Reason)
+	if utf8.RuneCountInString(updateRequest.Reason) > maxStatusReasonLength {
+		http.Error(w, fmt.Sprintf("Reason must be at most %d characters", maxStatusReasonLength), http.StatusBadRequest)
 		return
 	}
 
@@ -189,12 +231,34 @@ func (api *api) UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if order.SupplierID != user.ID {
+	if (user.Role == "supplier" && order.SupplierID != user.ID) || (user.Role == "client" && order.ClientID != user.ID) {
 		http.Error(w, "You can only update status of your own orders", http.StatusForbidden)
 		return
 	}
 
-	err = api.db.UpdateOrderStatus(orderID, updateRequest.Status)
+	// Переход проверяется по таблице статусов: откатить доставленный или отмененный заказ нельзя
+	err = order.CheckTransition(updateRequest.Status, user.Role)
+	switch {
+	case errors.Is(err, models.ErrTransitionForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	err = api.db.UpdateOrderStatus(models.OrderStatusChange{
+		OrderID:   orderID,
+		OldStatus: order.Status,
+		NewStatus: updateRequest.Status,
+		ActorID:   user.ID,
+		ActorRole: user.Role,
+		Reason:    updateRequest.Reason,
+	})
+	if errors.Is(err, repository.ErrOrderStatusChanged) {
+		http.Error(w, "Order status has changed, reload the order and try again", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to update order status", http.StatusInternalServerError)
 		return
@@ -202,17 +266,8 @@ func (api *api) UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request)
 
 	// Создали новое событие с обновлением статуса
 	if api.producer != nil {
-		orderEvent := models.OrderEvent{
-			EventType:   "order_status_updated",
-			OrderID:     orderID,
-			ProductName: order.ProductName,
-			ProductID:   order.ProductID,
-			SupplierID:  order.SupplierID,
-			ClientID:    order.ClientID,
-			Amount:      order.Amount,
-			Status:      updateRequest.Status,
-			Timestamp:   time.Now(),
-		}
+		order.Status = updateRequest.Status
+		orderEvent := models.NewOrderEvent("order_status_updated", *order)
 
 		// Отправили его в кафку
 		if err := api.producer.PublishMessage("order-events", orderEvent); err != nil {
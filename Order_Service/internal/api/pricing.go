@@ -0,0 +1,164 @@
+package api
+
+import (
+	"Order_Service/internal/models"
+	"Order_Service/internal/products"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+const (
+	maxOrderQuantity = 100
+	maxOrderItems    = 50
+)
+
+// priceOrder собирает заказ по данным Product Service: название, поставщика и цены клиент
+// задать не может. Цена позиции берется с учетом действующей акции, у товара с вариантами —
+// цена варианта. Все позиции заказа должны быть от одного поставщика.
+// При ошибке ответ уже записан в w.
+func (api *api) priceOrder(w http.ResponseWriter, r *http.Request, request models.CreateOrderRequest) (models.Order, bool) {
+	requested, err := mergeOrderItems(request.OrderItems())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return models.Order{}, false
+	}
+
+	authorization := r.Header.Get("Authorization")
+	fetched := make(map[int]products.Product)
+	items := make([]models.OrderItem, 0, len(requested))
+	supplierID := 0
+
+	for _, requestedItem := range requested {
+		product, ok := fetched[requestedItem.ProductID]
+		if !ok {
+			product, err = api.products.GetProduct(requestedItem.ProductID, authorization)
+			if errors.Is(err, products.ErrProductNotFound) {
+				http.Error(w, fmt.Sprintf("Product %d not found", requestedItem.ProductID), http.StatusNotFound)
+				return models.Order{}, false
+			}
+			if err != nil {
+				log.Printf("Failed to get product %d: %v", requestedItem.ProductID, err)
+				http.Error(w, "Product service unavailable", http.StatusBadGateway)
+				return models.Order{}, false
+			}
+			fetched[product.ID] = product
+		}
+
+		if supplierID == 0 {
+			supplierID = product.UserID
+		} else if product.UserID != supplierID {
+			http.Error(w, "All items of an order must be from the same supplier", http.StatusBadRequest)
+			return models.Order{}, false
+		}
+
+		unitPrice, stock, problem := productPrice(product, requestedItem.VariantID)
+		switch problem {
+		case models.CartProblemVariantNotFound:
+			http.Error(w, fmt.Sprintf("Variant %d of product %d not found", requestedItem.VariantID, product.ID), http.StatusNotFound)
+			return models.Order{}, false
+		case models.CartProblemVariantRequired:
+			http.Error(w, fmt.Sprintf("Variant id is required for product %d", product.ID), http.StatusBadRequest)
+			return models.Order{}, false
+		case models.CartProblemNoPrice:
+			http.Error(w, fmt.Sprintf("Product %d cannot be ordered at this price", product.ID), http.StatusConflict)
+			return models.Order{}, false
+		}
+
+		if stock < requestedItem.Quantity {
+			http.Error(w, fmt.Sprintf("Not enough stock for product %d", product.ID), http.StatusConflict)
+			return models.Order{}, false
+		}
+
+		items = append(items, models.OrderItem{
+			ProductID:   product.ID,
+			VariantID:   requestedItem.VariantID,
+			ProductName: product.Name,
+			Quantity:    requestedItem.Quantity,
+			UnitPrice:   unitPrice,
+			LineTotal:   unitPrice.Multiply(requestedItem.Quantity),
+		})
+	}
+
+	order, err := models.NewOrder(supplierID, items)
+	if err != nil {
+		http.Error(w, "All items of an order must be in the same currency", http.StatusBadRequest)
+		return models.Order{}, false
+	}
+
+	if request.SupplierID != 0 && request.SupplierID != order.SupplierID {
+		http.Error(w, "Supplier does not match the products", http.StatusConflict)
+		return models.Order{}, false
+	}
+	if request.Amount != nil && request.Amount.Normalize() != order.Amount {
+		http.Error(w, "Price has changed: order amount is "+order.Amount.String(), http.StatusConflict)
+		return models.Order{}, false
+	}
+
+	return order, true
+}
+
+// productPrice возвращает цену и остаток товара, а у товара с вариантами — выбранного варианта.
+// Если вариант не найден или не выбран, возвращается причина из models.CartProblem*.
+// Бесплатно товар не продается: нулевая цена означает ошибку в данных каталога.
+func productPrice(product products.Product, variantID int) (models.Money, int, string) {
+	price, stock := product.EffectivePrice, product.Stock
+	switch {
+	case variantID != 0:
+		variant, ok := product.Variant(variantID)
+		if !ok {
+			return models.Money{}, 0, models.CartProblemVariantNotFound
+		}
+		price, stock = variant.EffectivePrice, variant.Stock
+	case len(product.Variants) > 0:
+		return models.Money{}, 0, models.CartProblemVariantRequired
+	}
+
+	if price.Amount <= 0 {
+		return models.Money{}, 0, models.CartProblemNoPrice
+	}
+	return price, stock, ""
+}
+
+// mergeOrderItems проверяет позиции и объединяет повторы одного товара и варианта.
+// Нулевое количество означает одну штуку — так заказывали старые клиенты.
+func mergeOrderItems(items []models.OrderItemRequest) ([]models.OrderItemRequest, error) {
+	if len(items) == 0 {
+		return nil, errors.New("order must contain at least one item")
+	}
+	if len(items) > maxOrderItems {
+		return nil, fmt.Errorf("order must contain at most %d items", maxOrderItems)
+	}
+
+	type itemKey struct{ productID, variantID int }
+	merged := make([]models.OrderItemRequest, 0, len(items))
+	index := make(map[itemKey]int, len(items))
+
+	for _, item := range items {
+		if item.ProductID <= 0 {
+			return nil, errors.New("product id is required")
+		}
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		if item.Quantity < 0 {
+			return nil, fmt.Errorf("quantity must be between 1 and %d", maxOrderQuantity)
+		}
+
+		key := itemKey{item.ProductID, item.VariantID}
+		if i, ok := index[key]; ok {
+			merged[i].Quantity += item.Quantity
+		} else {
+			index[key] = len(merged)
+			merged = append(merged, item)
+		}
+	}
+
+	for _, item := range merged {
+		if item.Quantity > maxOrderQuantity {
+			return nil, fmt.Errorf("quantity must be between 1 and %d", maxOrderQuantity)
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,120 @@
+package kafka
+
+import (
+	"Order_Service/internal/models"
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+type Consumer struct {
+	brokers []string
+	topics  []string
+	handler PaymentEventHandler
+}
+
+type PaymentEventHandler interface {
+	HandlePaymentEvent(event models.PaymentEvent) error
+}
+
+func NewConsumer(brokers []string, topics []string, handler PaymentEventHandler) *Consumer {
+	return &Consumer{
+		brokers: brokers,
+		topics:  topics,
+		handler: handler,
+	}
+}
+
+func (c *Consumer) Start(ctx context.Context) error {
+	config := sarama.NewConfig()
+	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	config.Consumer.Return.Errors = true
+
+	consumerGroup, err := sarama.NewConsumerGroup(c.brokers, "order-service", config)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := consumerGroup.Close(); err != nil {
+			log.Printf("Error closing consumer group: %v", err)
+		}
+	}()
+
+	go func() {
+		for err := range consumerGroup.Errors() {
+			log.Printf("Consumer error: %v", err)
+		}
+	}()
+
+	consumer := &consumerGroupHandler{handler: c.handler}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Terminating: context cancelled")
+			return nil
+		default:
+			if err := consumerGroup.Consume(ctx, c.topics, consumer); err != nil {
+				log.Printf("Error from consumer: %v", err)
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}
+}
+
+type consumerGroupHandler struct {
+	handler PaymentEventHandler
+}
+
+func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case message := <-claim.Messages():
+			if message == nil {
+				return nil
+			}
+
+			log.Printf("Received message from topic %s: %s", message.Topic, string(message.Value))
+
+			var messageData map[string]interface{}
+			if err := json.Unmarshal(message.Value, &messageData); err != nil {
+				log.Printf("Error unmarshaling message: %v", err)
+				session.MarkMessage(message, "")
+				continue
+			}
+
+			eventType, ok := messageData["event_type"].(string)
+			if !ok {
+				log.Printf("No event_type found in message")
+				session.MarkMessage(message, "")
+				continue
+			}
+
+			// Остальные события топика, в том числе собственные события заказов, Order Service не обрабатывает
+			switch eventType {
+			case "payment_completed":
+				var paymentEvent models.PaymentEvent
+				if err := json.Unmarshal(message.Value, &paymentEvent); err == nil {
+					if err := h.handler.HandlePaymentEvent(paymentEvent); err != nil {
+						log.Printf("Error handling payment event: %v", err)
+					}
+				} else {
+					log.Printf("Error unmarshaling PaymentEvent: %v", err)
+				}
+			}
+
+			session.MarkMessage(message, "")
+
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
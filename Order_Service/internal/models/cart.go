@@ -0,0 +1,47 @@
+package models
+
+// Причины, по которым позицию корзины нельзя оформить
+const (
+	CartProblemNotFound          = "not_found"
+	CartProblemVariantNotFound   = "variant_not_found"
+	CartProblemVariantRequired   = "variant_required"
+	CartProblemNoPrice           = "no_price"
+	CartProblemInsufficientStock = "insufficient_stock"
+)
+
+// Cart — корзина клиента или анонимная корзина, доступная по токену X-Cart-Token.
+// Totals — суммы по валютам: в корзине могут лежать товары разных поставщиков.
+type Cart struct {
+	ID          int        `json:"-"`
+	Token       string     `json:"token,omitempty"`
+	Items       []CartItem `json:"items"`
+	Totals      []Money    `json:"totals"`
+	CanCheckout bool       `json:"can_checkout"`
+}
+
+// CartItem — позиция корзины. UnitPrice — цена, которую клиент видел последней; при каждом
+// просмотре она обновляется по Product Service, а прежняя цена попадает в PreviousPrice.
+type CartItem struct {
+	ProductID     int    `json:"product_id"`
+	VariantID     int    `json:"variant_id,omitempty"`
+	Quantity      int    `json:"quantity"`
+	ProductName   string `json:"product_name,omitempty"`
+	SupplierID    int    `json:"supplier_id,omitempty"`
+	UnitPrice     *Money `json:"unit_price,omitempty"`
+	PreviousPrice *Money `json:"previous_price,omitempty"`
+	LineTotal     *Money `json:"line_total,omitempty"`
+	Stock         int    `json:"stock"`
+	Available     bool   `json:"available"`
+	Problem       string `json:"problem,omitempty"`
+}
+
+type CartItemRequest struct {
+	ProductID int `json:"product_id"`
+	VariantID int `json:"variant_id,omitempty"`
+	Quantity  int `json:"quantity"`
+}
+
+// CheckoutResponse — заказы, созданные из корзины, по одному на поставщика.
+type CheckoutResponse struct {
+	Orders []Order `json:"orders"`
+}
@@ -2,14 +2,50 @@ package models
 
 import "time"
 
+// OrderEvent — событие заказа. Reason заполняется при автоматической отмене,
+// PaymentDeadline — в напоминании об оплате.
 type OrderEvent struct {
-	EventType   string    `json:"event_type"`
-	OrderID     int       `json:"order_id"`
-	ProductName string    `json:"product_name"`
-	ProductID   int       `json:"product_id"`
-	SupplierID  int       `json:"supplier_id"`
-	ClientID    int       `json:"client_id"`
-	Amount      float64   `json:"amount"`
-	Status      string    `json:"status,omitempty"`
-	Timestamp   time.Time `json:"timestamp"`
+	EventType       string      `json:"event_type"`
+	OrderID         int         `json:"order_id"`
+	ProductName     string      `json:"product_name"`
+	ProductID       int         `json:"product_id"`
+	VariantID       int         `json:"variant_id,omitempty"`
+	SupplierID      int         `json:"supplier_id"`
+	ClientID        int         `json:"client_id"`
+	Quantity        int         `json:"quantity"`
+	Amount          Money       `json:"amount"`
+	Items           []OrderItem `json:"items"`
+	Status          string      `json:"status,omitempty"`
+	Reason          string      `json:"reason,omitempty"`
+	PaymentDeadline *time.Time  `json:"payment_deadline,omitempty"`
+	Timestamp       time.Time   `json:"timestamp"`
+}
+
+// PaymentEvent — событие Payment Service. Для payment_completed в Status приходит completed или failed.
+type PaymentEvent struct {
+	EventType string    `json:"event_type"`
+	PaymentID string    `json:"payment_id"`
+	OrderID   int       `json:"order_id"`
+	ClientID  int       `json:"client_id"`
+	Amount    Money     `json:"amount"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewOrderEvent собирает событие заказа со всеми позициями.
+func NewOrderEvent(eventType string, order Order) OrderEvent {
+	return OrderEvent{
+		EventType:   eventType,
+		OrderID:     order.ID,
+		ProductName: order.ProductName,
+		ProductID:   order.ProductID,
+		VariantID:   order.VariantID,
+		SupplierID:  order.SupplierID,
+		ClientID:    order.ClientID,
+		Quantity:    order.Quantity,
+		Amount:      order.Amount,
+		Items:       order.Items,
+		Status:      order.Status,
+		Timestamp:   time.Now(),
+	}
 }
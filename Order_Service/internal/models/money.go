@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const DefaultCurrency = "RUB"
+
+// Количество знаков дробной части для поддерживаемых валют ISO 4217
+var currencyExponents = map[string]int{
+	"RUB": 2,
+	"USD": 2,
+	"EUR": 2,
+	"KZT": 2,
+	"BYN": 2,
+	"CNY": 2,
+	"JPY": 0,
+}
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrNegativeAmount   = errors.New("amount cannot be negative")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Money хранит сумму в минимальных единицах валюты (копейках, центах),
+// чтобы при сложении и умножении не накапливались ошибки округления.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// Normalize приводит код валюты к верхнему регистру и подставляет валюту по умолчанию.
+func (m Money) Normalize() Money {
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+	m.Currency = strings.ToUpper(m.Currency)
+	return m
+}
+
+func (m Money) Validate() error {
+	if _, ok := currencyExponents[m.Currency]; !ok {
+		return ErrUnknownCurrency
+	}
+	if m.Amount < 0 {
+		return ErrNegativeAmount
+	}
+	return nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Multiply(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.Currency}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) String() string {
+	exponent := currencyExponents[m.Currency]
+	if exponent == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	divisor := int64(1)
+	for i := 0; i < exponent; i++ {
+		divisor *= 10
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/divisor, exponent, amount%divisor, m.Currency)
+}
@@ -1,15 +1,88 @@
 package models
 
+import "time"
+
+// Order — заказ клиента у одного поставщика. Amount — сумма всех позиций Items.
+// ProductName, ProductID, VariantID и Quantity повторяют первую позицию для старых клиентов API.
+// PaymentTracked ложно у заказов, созданных до учета оплаты: их PaymentStatus может быть неверным.
 type Order struct {
-	ID          int     `json:"id"`
-	ProductName string  `json:"product_name"`
-	ProductID   int     `json:"product_id"`
-	SupplierID  int     `json:"supplier_id"`
-	ClientID    int     `json:"client_id"`
-	Amount      float64 `json:"amount"`
-	Status      string  `json:"status"`
+	ID             int         `json:"id"`
+	ProductName    string      `json:"product_name"`
+	ProductID      int         `json:"product_id"`
+	VariantID      int         `json:"variant_id,omitempty"`
+	SupplierID     int         `json:"supplier_id"`
+	ClientID       int         `json:"client_id"`
+	Quantity       int         `json:"quantity"`
+	Amount         Money       `json:"amount"`
+	Status         string      `json:"status"`
+	PaymentStatus  string      `json:"payment_status"`
+	PaymentTracked bool        `json:"-"`
+	CreatedAt      time.Time   `json:"created_at"`
+	Items          []OrderItem `json:"items"`
+}
+
+// OrderItem — позиция заказа. UnitPrice фиксирует цену на момент заказа, LineTotal = UnitPrice * Quantity.
+type OrderItem struct {
+	ProductID   int    `json:"product_id"`
+	VariantID   int    `json:"variant_id,omitempty"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   Money  `json:"unit_price"`
+	LineTotal   Money  `json:"line_total"`
 }
 
 type UpdateOrderStatusRequest struct {
 	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// CreateOrderRequest — то, что клиент может указать в заказе: позиции Items или, для заказа
+// одного товара, ProductID, VariantID и Quantity. Название, поставщика и сумму Order Service
+// берет из Product Service. Amount и SupplierID необязательны: если клиент их передал,
+// они должны совпасть с расчетом, иначе заказ отклоняется.
+type CreateOrderRequest struct {
+	Items      []OrderItemRequest `json:"items,omitempty"`
+	ProductID  int                `json:"product_id,omitempty"`
+	VariantID  int                `json:"variant_id,omitempty"`
+	Quantity   int                `json:"quantity,omitempty"`
+	Amount     *Money             `json:"amount,omitempty"`
+	SupplierID int                `json:"supplier_id,omitempty"`
+}
+
+type OrderItemRequest struct {
+	ProductID int `json:"product_id"`
+	VariantID int `json:"variant_id,omitempty"`
+	Quantity  int `json:"quantity"`
+}
+
+// OrderItems возвращает позиции запроса, включая заказ одного товара в старом формате.
+func (r CreateOrderRequest) OrderItems() []OrderItemRequest {
+	if len(r.Items) == 0 && r.ProductID != 0 {
+		return []OrderItemRequest{{ProductID: r.ProductID, VariantID: r.VariantID, Quantity: r.Quantity}}
+	}
+	return r.Items
+}
+
+// NewOrder собирает заказ из позиций: сумма считается по позициям, поля первой позиции
+// копируются в заказ. Все позиции должны быть в одной валюте.
+func NewOrder(supplierID int, items []OrderItem) (Order, error) {
+	order := Order{SupplierID: supplierID, Items: items}
+	if len(items) == 0 {
+		return order, nil
+	}
+
+	order.ProductName = items[0].ProductName
+	order.ProductID = items[0].ProductID
+	order.VariantID = items[0].VariantID
+	order.Quantity = items[0].Quantity
+	order.Amount = Money{Currency: items[0].LineTotal.Currency}
+
+	for _, item := range items {
+		total, err := order.Amount.Add(item.LineTotal)
+		if err != nil {
+			return order, err
+		}
+		order.Amount = total
+	}
+	return order, nil
 }
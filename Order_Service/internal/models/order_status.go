@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	OrderStatusPending       = "pending"
+	OrderStatusPaid          = "paid"
+	OrderStatusPaymentFailed = "payment_failed"
+	OrderStatusConfirmed     = "confirmed"
+	OrderStatusProcessing    = "processing"
+	OrderStatusShipped       = "shipped"
+	OrderStatusDelivered     = "delivered"
+	OrderStatusCancelled     = "cancelled"
+)
+
+// RoleSystem — роль для переходов, которые Order Service делает сам по событиям других сервисов
+const RoleSystem = "system"
+
+// Статус оплаты заказа по данным Payment Service
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusCompleted = "completed"
+	PaymentStatusFailed    = "failed"
+)
+
+var (
+	ErrUnknownOrderStatus      = errors.New("unknown order status")
+	ErrIllegalStatusTransition = errors.New("illegal order status transition")
+	ErrTransitionForbidden     = errors.New("status transition is not allowed for this role")
+	ErrOrderNotPaid            = errors.New("order is not paid")
+)
+
+// orderTransitions — разрешенные переходы статусов и роли, которым они доступны.
+// Из delivered и cancelled переходов нет.
+var orderTransitions = map[string]map[string][]string{
+	OrderStatusPending: {
+		OrderStatusPaid:          {RoleSystem},
+		OrderStatusPaymentFailed: {RoleSystem},
+		OrderStatusConfirmed:     {"supplier"},
+		OrderStatusCancelled:     {"supplier", "client", RoleSystem},
+	},
+	OrderStatusPaid: {
+		OrderStatusConfirmed: {"supplier"},
+		OrderStatusCancelled: {"supplier"},
+	},
+	OrderStatusPaymentFailed: {
+		OrderStatusCancelled: {"supplier", "client", RoleSystem},
+	},
+	OrderStatusConfirmed: {
+		OrderStatusPaymentFailed: {RoleSystem},
+		OrderStatusProcessing:    {"supplier"},
+		OrderStatusCancelled:     {"supplier", "client", RoleSystem},
+	},
+	OrderStatusProcessing: {
+		OrderStatusPaymentFailed: {RoleSystem},
+		OrderStatusShipped:       {"supplier"},
+		OrderStatusCancelled:     {"supplier"},
+	},
+	OrderStatusShipped: {
+		OrderStatusDelivered: {"supplier"},
+	},
+	OrderStatusDelivered: {},
+	OrderStatusCancelled: {},
+}
+
+func IsValidOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// StatusesAllowing возвращает статусы, из которых роль role может перевести заказ в status.
+func StatusesAllowing(status string, role string) []string {
+	var statuses []string
+	for from, transitions := range orderTransitions {
+		for _, r := range transitions[status] {
+			if r == role {
+				statuses = append(statuses, from)
+				break
+			}
+		}
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+// CheckTransition проверяет, может ли пользователь с ролью role перевести заказ в статус status.
+// Отгрузить заказ можно только после оплаты. У заказов, созданных до учета оплаты, она не проверяется.
+func (o Order) CheckTransition(status string, role string) error {
+	if !IsValidOrderStatus(status) {
+		return ErrUnknownOrderStatus
+	}
+
+	roles, ok := orderTransitions[o.Status][status]
+	if !ok {
+		return fmt.Errorf("%w: %s -> %s", ErrIllegalStatusTransition, o.Status, status)
+	}
+
+	allowed := false
+	for _, r := range roles {
+		if r == role {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s -> %s", ErrTransitionForbidden, o.Status, status)
+	}
+
+	if status == OrderStatusShipped && o.PaymentTracked && o.PaymentStatus != PaymentStatusCompleted {
+		return fmt.Errorf("%w: cannot ship before payment is completed", ErrOrderNotPaid)
+	}
+	return nil
+}
+
+// OrderStatusChange — запись истории статусов заказа: кто, когда и почему перевел заказ
+// из OldStatus в NewStatus. У записи о создании заказа OldStatus пуст, у переходов,
+// которые Order Service делает сам, ActorRole = system и ActorID = 0.
+type OrderStatusChange struct {
+	OrderID   int       `json:"order_id"`
+	OldStatus string    `json:"old_status,omitempty"`
+	NewStatus string    `json:"new_status"`
+	ActorID   int       `json:"actor_id"`
+	ActorRole string    `json:"actor_role"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
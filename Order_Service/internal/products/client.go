@@ -0,0 +1,85 @@
+package products
+
+import (
+	"Order_Service/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var ErrProductNotFound = errors.New("product not found")
+
+// Product — часть карточки товара из Product Service, нужная для расчета заказа.
+// EffectivePrice уже учитывает действующую акцию.
+type Product struct {
+	ID             int          `json:"id"`
+	Name           string       `json:"name"`
+	UserID         int          `json:"user_id"`
+	EffectivePrice models.Money `json:"effective_price"`
+	Stock          int          `json:"stock"`
+	Variants       []Variant    `json:"variants"`
+}
+
+type Variant struct {
+	ID             int          `json:"id"`
+	SKU            string       `json:"sku"`
+	EffectivePrice models.Money `json:"effective_price"`
+	Stock          int          `json:"stock"`
+}
+
+// Variant ищет вариант товара по id.
+func (p Product) Variant(id int) (Variant, bool) {
+	for _, variant := range p.Variants {
+		if variant.ID == id {
+			return variant, true
+		}
+	}
+	return Variant{}, false
+}
+
+// Client обращается к Product Service по HTTP от имени пользователя, сделавшего запрос
+// (или гостя, если токена нет): Product Service сам решает, виден ли ему товар.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+func (c *Client) GetProduct(id int, authorization string) (Product, error) {
+	var product Product
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/product/%d", c.baseURL, id), nil)
+	if err != nil {
+		return product, err
+	}
+	// Без токена Product Service отдает карточку как гостю — так работает анонимная корзина
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return product, fmt.Errorf("product service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return product, ErrProductNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return product, fmt.Errorf("failed to get product %d: status %d", id, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		return product, fmt.Errorf("failed to decode product %d: %w", id, err)
+	}
+	return product, nil
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"Order_Service/internal/models"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrCartNotFound     = errors.New("cart not found")
+	ErrCartItemNotFound = errors.New("cart item not found")
+	ErrCartChanged      = errors.New("cart changed during checkout")
+	ErrCartFull         = errors.New("too many items in cart")
+)
+
+// GetClientCart возвращает корзину клиента, создавая ее при первом обращении.
+func (repo *PGRepo) GetClientCart(clientID int) (models.Cart, error) {
+	cart := models.Cart{}
+	err := repo.pool.QueryRow(context.Background(),
+		`INSERT INTO carts (client_id) VALUES ($1)
+		ON CONFLICT (client_id) DO UPDATE SET client_id = EXCLUDED.client_id
+		RETURNING id`, clientID).Scan(&cart.ID)
+	if err != nil {
+		return cart, err
+	}
+
+	cart.Items, err = repo.GetCartItems(cart.ID)
+	return cart, err
+}
+
+func (repo *PGRepo) CreateGuestCart(token string) (models.Cart, error) {
+	cart := models.Cart{Token: token, Items: []models.CartItem{}}
+	err := repo.pool.QueryRow(context.Background(),
+		`INSERT INTO carts (token) VALUES ($1) RETURNING id`, token).Scan(&cart.ID)
+	return cart, err
+}
+
+func (repo *PGRepo) GetGuestCart(token string) (models.Cart, error) {
+	cart := models.Cart{Token: token}
+	err := repo.pool.QueryRow(context.Background(),
+		`SELECT id FROM carts WHERE token = $1 AND client_id IS NULL`, token).Scan(&cart.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return cart, ErrCartNotFound
+	}
+	if err != nil {
+		return cart, err
+	}
+
+	cart.Items, err = repo.GetCartItems(cart.ID)
+	return cart, err
+}
+
+// GetCartItems возвращает позиции в порядке добавления вместе с последней показанной ценой.
+func (repo *PGRepo) GetCartItems(cartID int) ([]models.CartItem, error) {
+	items := []models.CartItem{}
+	rows, err := repo.pool.Query(context.Background(),
+		`SELECT product_id, variant_id, quantity, price_minor, currency
+		FROM cart_items WHERE cart_id = $1 ORDER BY added_at, product_id, variant_id`, cartID)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.CartItem
+		var priceMinor *int64
+		var currency *string
+		if err := rows.Scan(&item.ProductID, &item.VariantID, &item.Quantity, &priceMinor, &currency); err != nil {
+			return items, err
+		}
+		if priceMinor != nil && currency != nil {
+			price := models.NewMoney(*priceMinor, *currency)
+			item.UnitPrice = &price
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// AddCartItem добавляет товар в корзину или увеличивает количество уже лежащей позиции,
+// не превышая maxQuantity.
+func (repo *PGRepo) AddCartItem(cartID int, item models.CartItemRequest, maxQuantity int) error {
+	_, err := repo.pool.Exec(context.Background(),
+		`INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES ($1, $2, $3, LEAST($4, $5))
+		ON CONFLICT (cart_id, product_id, variant_id) DO UPDATE SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, $5)`,
+		cartID, item.ProductID, item.VariantID, item.Quantity, maxQuantity)
+	if err != nil {
+		return err
+	}
+	return repo.touchCart(cartID)
+}
+
+func (repo *PGRepo) SetCartItemQuantity(cartID int, item models.CartItemRequest) error {
+	tag, err := repo.pool.Exec(context.Background(),
+		`UPDATE cart_items SET quantity = $4 WHERE cart_id = $1 AND product_id = $2 AND variant_id = $3`,
+		cartID, item.ProductID, item.VariantID, item.Quantity)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCartItemNotFound
+	}
+	return repo.touchCart(cartID)
+}
+
+func (repo *PGRepo) DeleteCartItem(cartID, productID, variantID int) error {
+	tag, err := repo.pool.Exec(context.Background(),
+		`DELETE FROM cart_items WHERE cart_id = $1 AND product_id = $2 AND variant_id = $3`,
+		cartID, productID, variantID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCartItemNotFound
+	}
+	return repo.touchCart(cartID)
+}
+
+func (repo *PGRepo) ClearCart(cartID int) error {
+	_, err := repo.pool.Exec(context.Background(), `DELETE FROM cart_items WHERE cart_id = $1`, cartID)
+	if err != nil {
+		return err
+	}
+	return repo.touchCart(cartID)
+}
+
+// UpdateCartPrices запоминает цены, которые клиент только что увидел: при следующем просмотре
+// изменение цены сравнивается с ними.
+func (repo *PGRepo) UpdateCartPrices(cartID int, items []models.CartItem) error {
+	for _, item := range items {
+		if item.UnitPrice == nil {
+			continue
+		}
+		_, err := repo.pool.Exec(context.Background(),
+			`UPDATE cart_items SET price_minor = $4, currency = $5 WHERE cart_id = $1 AND product_id = $2 AND variant_id = $3`,
+			cartID, item.ProductID, item.VariantID, item.UnitPrice.Amount, item.UnitPrice.Currency)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeGuestCart переносит позиции анонимной корзины в корзину клиента и удаляет анонимную.
+// Количество одинаковых позиций складывается, но не превышает maxQuantity. Если позиций в корзине
+// клиента станет больше maxItems, корзины не сливаются и возвращается ErrCartFull.
+func (repo *PGRepo) MergeGuestCart(token string, clientID int, maxItems int, maxQuantity int) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	var guestCartID int
+	err = tx.QueryRow(context.Background(),
+		`SELECT id FROM carts WHERE token = $1 AND client_id IS NULL FOR UPDATE`, token).Scan(&guestCartID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCartNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var clientCartID int
+	err = tx.QueryRow(context.Background(),
+		`INSERT INTO carts (client_id) VALUES ($1)
+		ON CONFLICT (client_id) DO UPDATE SET updated_at = NOW()
+		RETURNING id`, clientID).Scan(&clientCartID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(context.Background(),
+		`INSERT INTO cart_items (cart_id, product_id, variant_id, quantity, price_minor, currency, added_at)
+		SELECT $2, product_id, variant_id, quantity, price_minor, currency, added_at
+		FROM cart_items WHERE cart_id = $1
+		ON CONFLICT (cart_id, product_id, variant_id) DO UPDATE SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, $3)`,
+		guestCartID, clientCartID, maxQuantity)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.QueryRow(context.Background(), `SELECT COUNT(*) FROM cart_items WHERE cart_id = $1`, clientCartID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > maxItems {
+		return ErrCartFull
+	}
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM carts WHERE id = $1`, guestCartID); err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// CheckoutCart в одной транзакции создает заказы, заполняя их ID и CreatedAt, и убирает оформленные
+// позиции из корзины. Если позиции успели измениться с момента проверки, возвращается ErrCartChanged
+// и заказы не создаются.
+func (repo *PGRepo) CheckoutCart(cartID int, items []models.CartItem, orders []models.Order) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	for _, item := range items {
+		tag, err := tx.Exec(context.Background(),
+			`DELETE FROM cart_items WHERE cart_id = $1 AND product_id = $2 AND variant_id = $3 AND quantity = $4`,
+			cartID, item.ProductID, item.VariantID, item.Quantity)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrCartChanged
+		}
+	}
+
+	for i := range orders {
+		if err := insertOrder(tx, &orders[i]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(context.Background(), `UPDATE carts SET updated_at = NOW() WHERE id = $1`, cartID); err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (repo *PGRepo) touchCart(cartID int) error {
+	_, err := repo.pool.Exec(context.Background(), `UPDATE carts SET updated_at = NOW() WHERE id = $1`, cartID)
+	return err
+}
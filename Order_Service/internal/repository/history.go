@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"Order_Service/internal/models"
+	"context"
+
+	"github.com/jackc/pgx/v4"
+)
+
+func insertStatusChange(tx pgx.Tx, change models.OrderStatusChange) error {
+	var oldStatus *string
+	if change.OldStatus != "" {
+		oldStatus = &change.OldStatus
+	}
+
+	_, err := tx.Exec(context.Background(),
+		`INSERT INTO order_status_history (order_id, old_status, new_status, actor_id, actor_role, reason) VALUES ($1, $2, $3, $4, $5, $6)`,
+		change.OrderID, oldStatus, change.NewStatus, change.ActorID, change.ActorRole, change.Reason)
+	return err
+}
+
+// GetOrderStatusHistory возвращает историю статусов заказа в хронологическом порядке.
+func (repo *PGRepo) GetOrderStatusHistory(orderID int) ([]models.OrderStatusChange, error) {
+	history := []models.OrderStatusChange{}
+	rows, err := repo.pool.Query(context.Background(),
+		`SELECT order_id, COALESCE(old_status, ''), new_status, actor_id, actor_role, reason, created_at
+		FROM order_status_history WHERE order_id = $1 ORDER BY created_at, id`, orderID)
+	if err != nil {
+		return history, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change models.OrderStatusChange
+		err := rows.Scan(&change.OrderID, &change.OldStatus, &change.NewStatus, &change.ActorID,
+			&change.ActorRole, &change.Reason, &change.CreatedAt)
+		if err != nil {
+			return history, err
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
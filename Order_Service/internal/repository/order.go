@@ -3,75 +3,233 @@ package repository
 import (
 	"Order_Service/internal/models"
 	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
 )
 
+var ErrOrderStatusChanged = errors.New("order status was changed concurrently")
+
+const orderColumns = `id, product_name, product_id, variant_id, supplier_id, client_id, quantity, amount_minor, currency, status, payment_status, COALESCE(payment_tracked, FALSE), created_at`
+
+func scanOrder(row pgx.Row) (models.Order, error) {
+	var order models.Order
+	err := row.Scan(&order.ID, &order.ProductName, &order.ProductID, &order.VariantID, &order.SupplierID, &order.ClientID,
+		&order.Quantity, &order.Amount.Amount, &order.Amount.Currency, &order.Status, &order.PaymentStatus, &order.PaymentTracked, &order.CreatedAt)
+	return order, err
+}
+
+// CreateOrder сохраняет заказ вместе с позициями в одной транзакции.
 func (repo *PGRepo) CreateOrder(order models.Order) (int, error) {
-	err := repo.pool.QueryRow(context.Background(),
-		`INSERT INTO orders (product_name, product_id, supplier_id, client_id, amount, status) VALUES ($1, $2, $3, $4, $5, $6) RETURNING ID`,
-		order.ProductName, order.ProductID, order.SupplierID, order.ClientID, order.Amount, "pending").Scan(&order.ID)
+	tx, err := repo.pool.Begin(context.Background())
 	if err != nil {
 		return 0, err
 	}
+	defer tx.Rollback(context.Background())
+
+	if err := insertOrder(tx, &order); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return 0, err
+	}
 	return order.ID, nil
 }
 
-func (repo *PGRepo) GetAllOrdersByClientID(clientID int) ([]models.Order, error) {
-	var orders []models.Order
-	rows, err := repo.pool.Query(context.Background(), `SELECT id, product_name, product_id, supplier_id, client_id, amount, status FROM orders WHERE client_id = $1`, clientID)
+// insertOrder сохраняет заказ с позициями и заполняет его ID и CreatedAt.
+func insertOrder(tx pgx.Tx, order *models.Order) error {
+	err := tx.QueryRow(context.Background(),
+		`INSERT INTO orders (product_name, product_id, variant_id, supplier_id, client_id, quantity, amount_minor, currency, status) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`,
+		order.ProductName, order.ProductID, order.VariantID, order.SupplierID, order.ClientID, order.Quantity, order.Amount.Amount, order.Amount.Currency, models.OrderStatusPending).Scan(&order.ID, &order.CreatedAt)
 	if err != nil {
-		return orders, err
+		return err
 	}
-	for rows.Next() {
-		var order models.Order
-		err := rows.Scan(&order.ID, &order.ProductName, &order.ProductID, &order.SupplierID, &order.ClientID, &order.Amount, &order.Status)
+
+	for _, item := range order.Items {
+		_, err := tx.Exec(context.Background(),
+			`INSERT INTO order_items (order_id, product_id, variant_id, product_name, quantity, unit_price_minor, currency, line_total_minor) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			order.ID, item.ProductID, item.VariantID, item.ProductName, item.Quantity, item.UnitPrice.Amount, item.UnitPrice.Currency, item.LineTotal.Amount)
 		if err != nil {
-			return orders, err
+			return err
 		}
-		orders = append(orders, order)
 	}
-	return orders, nil
+
+	return insertStatusChange(tx, models.OrderStatusChange{
+		OrderID:   order.ID,
+		NewStatus: models.OrderStatusPending,
+		ActorID:   order.ClientID,
+		ActorRole: "client",
+	})
+}
+
+func (repo *PGRepo) GetAllOrdersByClientID(clientID int) ([]models.Order, error) {
+	return repo.queryOrders(`SELECT `+orderColumns+` FROM orders WHERE client_id = $1`, clientID)
 }
 
 func (repo *PGRepo) GetAllOrdersBySupplierID(supplierID int) ([]models.Order, error) {
+	return repo.queryOrders(`SELECT `+orderColumns+` FROM orders WHERE supplier_id = $1`, supplierID)
+}
+
+func (repo *PGRepo) GetOrderByID(id int) (*models.Order, error) {
+	order, err := scanOrder(repo.pool.QueryRow(context.Background(), `SELECT `+orderColumns+` FROM orders WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	orders := []models.Order{order}
+	if err := repo.attachItems(orders); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+// UpdateOrderStatus переводит заказ change.OrderID из change.OldStatus в change.NewStatus и записывает
+// переход в историю. Если статус заказа успели изменить с момента проверки перехода,
+// возвращается ErrOrderStatusChanged.
+func (repo *PGRepo) UpdateOrderStatus(change models.OrderStatusChange) error {
+	return repo.changeOrderStatus(change, "")
+}
+
+// CancelUnpaidOrder отменяет заказ change.OrderID, если он все еще в статусе change.OldStatus и не оплачен.
+// Оплата подтвержденного заказа не меняет его статус, поэтому одной проверки статуса мало: оплата могла
+// прийти после выборки неоплаченных заказов. Если заказ успели изменить или оплатить, возвращается ErrOrderStatusChanged.
+func (repo *PGRepo) CancelUnpaidOrder(change models.OrderStatusChange) error {
+	change.NewStatus = models.OrderStatusCancelled
+	return repo.changeOrderStatus(change, `AND payment_status <> 'completed'`)
+}
+
+// changeOrderStatus меняет статус заказа с дополнительным условием condition к UPDATE и записывает переход в историю.
+func (repo *PGRepo) changeOrderStatus(change models.OrderStatusChange, condition string) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	tag, err := tx.Exec(context.Background(), `UPDATE orders SET status = $1 WHERE id = $2 AND status = $3 `+condition,
+		change.NewStatus, change.OrderID, change.OldStatus)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrOrderStatusChanged
+	}
+
+	if err := insertStatusChange(tx, change); err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+// ApplyPaymentResult записывает результат оплаты и переводит заказ из change.OldStatus в change.NewStatus.
+// В историю попадает только смена статуса. Если статус успели изменить, возвращается ErrOrderStatusChanged.
+func (repo *PGRepo) ApplyPaymentResult(change models.OrderStatusChange, paymentStatus string) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	tag, err := tx.Exec(context.Background(),
+		`UPDATE orders SET status = $1, payment_status = $2 WHERE id = $3 AND status = $4`,
+		change.NewStatus, paymentStatus, change.OrderID, change.OldStatus)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrOrderStatusChanged
+	}
+
+	if change.NewStatus != change.OldStatus {
+		if err := insertStatusChange(tx, change); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(context.Background())
+}
+
+// Сколько неоплаченных заказов обрабатывать за одну проверку
+const unpaidOrdersBatch = 500
+
+// GetUnpaidOrdersCreatedBefore возвращает неоплаченные заказы в статусах statuses, созданные не позже before.
+// Заказы, созданные до учета оплаты (payment_tracked пуст), не возвращаются: их оплата неизвестна.
+func (repo *PGRepo) GetUnpaidOrdersCreatedBefore(before time.Time, statuses []string) ([]models.Order, error) {
+	return repo.queryOrders(`SELECT `+orderColumns+` FROM orders
+		WHERE payment_status <> 'completed' AND payment_tracked AND status = ANY($1) AND created_at <= $2
+		ORDER BY created_at LIMIT $3`, statuses, before, unpaidOrdersBatch)
+}
+
+// GetOrdersForPaymentReminder возвращает неоплаченные заказы, созданные не позже before,
+// которым еще не отправляли напоминание об оплате. Заказы, созданные до учета оплаты, не возвращаются.
+func (repo *PGRepo) GetOrdersForPaymentReminder(before time.Time, statuses []string) ([]models.Order, error) {
+	return repo.queryOrders(`SELECT `+orderColumns+` FROM orders
+		WHERE payment_status <> 'completed' AND payment_tracked AND status = ANY($1) AND created_at <= $2 AND payment_reminder_sent_at IS NULL
+		ORDER BY created_at LIMIT $3`, statuses, before, unpaidOrdersBatch)
+}
+
+func (repo *PGRepo) MarkPaymentReminderSent(id int) error {
+	_, err := repo.pool.Exec(context.Background(), `UPDATE orders SET payment_reminder_sent_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+func (repo *PGRepo) queryOrders(query string, args ...interface{}) ([]models.Order, error) {
 	var orders []models.Order
-	rows, err := repo.pool.Query(context.Background(), `SELECT id, product_name, product_id, supplier_id, client_id, amount, status FROM orders WHERE supplier_id = $1`, supplierID)
+	rows, err := repo.pool.Query(context.Background(), query, args...)
 	if err != nil {
 		return orders, err
 	}
+	defer rows.Close()
+
 	for rows.Next() {
-		var order models.Order
-		err := rows.Scan(&order.ID, &order.ProductName, &order.ProductID, &order.SupplierID, &order.ClientID, &order.Amount, &order.Status)
+		order, err := scanOrder(rows)
 		if err != nil {
 			return orders, err
 		}
 		orders = append(orders, order)
 	}
-	return orders, nil
+	if err := rows.Err(); err != nil {
+		return orders, err
+	}
+
+	return orders, repo.attachItems(orders)
 }
 
-func (repo *PGRepo) DeleteOrderByID(id int, clientID int) error {
-	_, err := repo.pool.Exec(context.Background(), `DELETE FROM orders WHERE id = $1 AND client_id = $2`, id, clientID)
-	if err != nil {
-		return err
+// attachItems подгружает позиции для заказов одним запросом.
+func (repo *PGRepo) attachItems(orders []models.Order) error {
+	if len(orders) == 0 {
+		return nil
 	}
-	return nil
-}
 
-func (repo *PGRepo) GetOrderByID(id int) (*models.Order, error) {
-	var order models.Order
-	err := repo.pool.QueryRow(context.Background(),
-		`SELECT id, product_name, product_id, supplier_id, client_id, amount, status FROM orders WHERE id = $1`,
-		id).Scan(&order.ID, &order.ProductName, &order.ProductID, &order.SupplierID, &order.ClientID, &order.Amount, &order.Status)
-	if err != nil {
-		return nil, err
+	ids := make([]int, 0, len(orders))
+	index := make(map[int]int, len(orders))
+	for i, order := range orders {
+		ids = append(ids, order.ID)
+		index[order.ID] = i
+		orders[i].Items = []models.OrderItem{}
 	}
-	return &order, nil
-}
 
-func (repo *PGRepo) UpdateOrderStatus(id int, status string) error {
-	_, err := repo.pool.Exec(context.Background(), `UPDATE orders SET status = $1 WHERE id = $2`, status, id)
+	rows, err := repo.pool.Query(context.Background(),
+		`SELECT order_id, product_id, variant_id, product_name, quantity, unit_price_minor, currency, line_total_minor
+		FROM order_items WHERE order_id = ANY($1) ORDER BY order_id, id`, ids)
 	if err != nil {
 		return err
 	}
-	return nil
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID int
+		var item models.OrderItem
+		err := rows.Scan(&orderID, &item.ProductID, &item.VariantID, &item.ProductName, &item.Quantity,
+			&item.UnitPrice.Amount, &item.UnitPrice.Currency, &item.LineTotal.Amount)
+		if err != nil {
+			return err
+		}
+		item.LineTotal.Currency = item.UnitPrice.Currency
+
+		i := index[orderID]
+		orders[i].Items = append(orders[i].Items, item)
+	}
+	return rows.Err()
 }
@@ -16,5 +16,128 @@ func NewPGRepo(addrStr string) (*PGRepo, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if err := createOrdersSchema(pool); err != nil {
+		return nil, err
+	}
+
 	return &PGRepo{mu: &sync.Mutex{}, pool: pool}, nil
 }
+
+func createOrdersSchema(pool *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS orders (
+		id SERIAL PRIMARY KEY,
+		product_name TEXT NOT NULL,
+		product_id INTEGER NOT NULL,
+		supplier_id INTEGER NOT NULL,
+		client_id INTEGER NOT NULL,
+		amount_minor BIGINT NOT NULL DEFAULT 0,
+		currency CHAR(3) NOT NULL DEFAULT 'RUB',
+		status VARCHAR(20) NOT NULL DEFAULT 'pending'
+	);
+
+	-- Старые суммы хранились в DECIMAL(10,2) в колонке amount, переводим их в копейки
+	DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'orders' AND column_name = 'amount') THEN
+			ALTER TABLE orders ADD COLUMN IF NOT EXISTS amount_minor BIGINT NOT NULL DEFAULT 0;
+			UPDATE orders SET amount_minor = ROUND(COALESCE(amount, 0) * 100)::BIGINT;
+			ALTER TABLE orders DROP COLUMN amount;
+		END IF;
+	END $$;
+
+	ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB';
+	ALTER TABLE orders ADD COLUMN IF NOT EXISTS variant_id INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE orders ADD COLUMN IF NOT EXISTS quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0);
+	ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+	-- До учета оплаты Order Service не получал события Payment Service, и прошлые события он уже не прочитает.
+	-- У заказов, созданных до учета, payment_tracked пуст: статус оплаты неизвестен. Заказы, которые поставщик
+	-- уже взял в работу, считаем оплаченными, остальные не отменяем автоматически по сроку оплаты.
+	ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_tracked BOOLEAN;
+	ALTER TABLE orders ALTER COLUMN payment_tracked SET DEFAULT TRUE;
+	UPDATE orders SET payment_status = 'completed'
+	WHERE payment_tracked IS NULL AND status IN ('processing', 'shipped', 'delivered') AND payment_status <> 'completed';
+	ALTER TABLE orders ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+	ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_reminder_sent_at TIMESTAMPTZ;
+
+	CREATE INDEX IF NOT EXISTS idx_orders_client_id ON orders(client_id);
+	CREATE INDEX IF NOT EXISTS idx_orders_supplier_id ON orders(supplier_id);
+	CREATE INDEX IF NOT EXISTS idx_orders_unpaid ON orders(created_at) WHERE payment_status <> 'completed';
+
+	CREATE TABLE IF NOT EXISTS order_items (
+		id SERIAL PRIMARY KEY,
+		order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+		product_id INTEGER NOT NULL,
+		variant_id INTEGER NOT NULL DEFAULT 0,
+		product_name TEXT NOT NULL,
+		quantity INTEGER NOT NULL CHECK (quantity > 0),
+		unit_price_minor BIGINT NOT NULL,
+		currency CHAR(3) NOT NULL DEFAULT 'RUB',
+		line_total_minor BIGINT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items(order_id);
+
+	-- Заказы, созданные до появления позиций, превращаем в заказы из одной позиции
+	INSERT INTO order_items (order_id, product_id, variant_id, product_name, quantity, unit_price_minor, currency, line_total_minor)
+	SELECT o.id, o.product_id, o.variant_id, o.product_name, o.quantity, o.amount_minor / o.quantity, o.currency, o.amount_minor
+	FROM orders o
+	WHERE NOT EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = o.id);
+
+	-- Если сумма прежнего заказа не делится на количество, цена единицы выше округлена вниз. Такую позицию
+	-- делим на две с ценами a/q и a/q + 1, чтобы в каждой цена единицы, умноженная на количество, давала сумму
+	INSERT INTO order_items (order_id, product_id, variant_id, product_name, quantity, unit_price_minor, currency, line_total_minor)
+	SELECT i.order_id, i.product_id, i.variant_id, i.product_name, i.line_total_minor % i.quantity,
+		i.line_total_minor / i.quantity + 1, i.currency, (i.line_total_minor % i.quantity) * (i.line_total_minor / i.quantity + 1)
+	FROM order_items i
+	WHERE i.unit_price_minor * i.quantity <> i.line_total_minor AND i.unit_price_minor = i.line_total_minor / i.quantity;
+
+	UPDATE order_items SET quantity = quantity - line_total_minor % quantity,
+		line_total_minor = (quantity - line_total_minor % quantity) * unit_price_minor
+	WHERE unit_price_minor * quantity <> line_total_minor AND unit_price_minor = line_total_minor / quantity;
+
+	CREATE TABLE IF NOT EXISTS order_status_history (
+		id SERIAL PRIMARY KEY,
+		order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+		old_status VARCHAR(20),
+		new_status VARCHAR(20) NOT NULL,
+		actor_id INTEGER NOT NULL DEFAULT 0,
+		actor_role VARCHAR(20) NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history(order_id, created_at);
+
+	-- Для заказов, созданных до появления истории, фиксируем текущий статус как ее начало
+	INSERT INTO order_status_history (order_id, new_status, actor_role, reason, created_at)
+	SELECT o.id, o.status, 'system', 'history_started', NOW()
+	FROM orders o
+	WHERE NOT EXISTS (SELECT 1 FROM order_status_history h WHERE h.order_id = o.id);
+
+	-- Корзина принадлежит либо клиенту (client_id), либо гостю (token)
+	CREATE TABLE IF NOT EXISTS carts (
+		id SERIAL PRIMARY KEY,
+		client_id INTEGER UNIQUE,
+		token TEXT UNIQUE,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		CHECK (client_id IS NOT NULL OR token IS NOT NULL)
+	);
+
+	CREATE TABLE IF NOT EXISTS cart_items (
+		cart_id INTEGER NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+		product_id INTEGER NOT NULL,
+		variant_id INTEGER NOT NULL DEFAULT 0,
+		quantity INTEGER NOT NULL CHECK (quantity > 0),
+		price_minor BIGINT,
+		currency CHAR(3),
+		added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (cart_id, product_id, variant_id)
+	);
+	`
+
+	_, err := pool.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"Order_Service/internal/kafka"
+	"Order_Service/internal/models"
+	"Order_Service/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Сколько раз перечитывать заказ, если его статус изменили одновременно с обработкой оплаты
+const paymentUpdateAttempts = 3
+
+type PaymentRepository interface {
+	GetOrderByID(id int) (*models.Order, error)
+	ApplyPaymentResult(change models.OrderStatusChange, paymentStatus string) error
+}
+
+// PaymentService переводит заказы по результатам оплаты из Payment Service.
+type PaymentService struct {
+	repo     PaymentRepository
+	producer *kafka.Producer
+}
+
+func NewPaymentService(repo PaymentRepository, producer *kafka.Producer) *PaymentService {
+	return &PaymentService{
+		repo:     repo,
+		producer: producer,
+	}
+}
+
+// HandlePaymentEvent по payment_completed записывает статус оплаты заказа. Успешная оплата
+// переводит ожидающий заказ в paid (подтвержденный поставщиком заказ остается confirmed),
+// неудачная — в payment_failed. Повторно доставленное событие ничего не меняет.
+func (ps *PaymentService) HandlePaymentEvent(event models.PaymentEvent) error {
+	if event.EventType != "payment_completed" {
+		return nil
+	}
+
+	var paymentStatus string
+	switch event.Status {
+	case models.PaymentStatusCompleted, models.PaymentStatusFailed:
+		paymentStatus = event.Status
+	default:
+		return nil
+	}
+
+	for attempt := 0; attempt < paymentUpdateAttempts; attempt++ {
+		order, err := ps.repo.GetOrderByID(event.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order %d: %w", event.OrderID, err)
+		}
+
+		if order.PaymentStatus == paymentStatus {
+			return nil
+		}
+
+		status := order.Status
+		target := models.OrderStatusPaid
+		if paymentStatus == models.PaymentStatusFailed {
+			target = models.OrderStatusPaymentFailed
+		}
+		if order.CheckTransition(target, models.RoleSystem) == nil {
+			status = target
+		} else if order.Status == models.OrderStatusCancelled && paymentStatus == models.PaymentStatusCompleted {
+			log.Printf("Order %d was paid after cancellation, payment %s needs a refund", order.ID, event.PaymentID)
+		}
+
+		err = ps.repo.ApplyPaymentResult(models.OrderStatusChange{
+			OrderID:   order.ID,
+			OldStatus: order.Status,
+			NewStatus: status,
+			ActorRole: models.RoleSystem,
+			Reason:    "payment_" + paymentStatus,
+		}, paymentStatus)
+		if errors.Is(err, repository.ErrOrderStatusChanged) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply payment result to order %d: %w", order.ID, err)
+		}
+
+		if status != order.Status {
+			order.Status = status
+			order.PaymentStatus = paymentStatus
+			ps.publishStatusUpdated(*order)
+		}
+		log.Printf("Order %d payment %s, status %s", order.ID, paymentStatus, order.Status)
+		return nil
+	}
+
+	return fmt.Errorf("order %d status keeps changing, payment %s not applied", event.OrderID, event.PaymentID)
+}
+
+func (ps *PaymentService) publishStatusUpdated(order models.Order) {
+	if ps.producer == nil {
+		return
+	}
+
+	orderEvent := models.NewOrderEvent("order_status_updated", order)
+	if err := ps.producer.PublishMessage("order-events", orderEvent); err != nil {
+		log.Printf("Failed to publish order status updated event: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"Order_Service/internal/kafka"
+	"Order_Service/internal/models"
+	"Order_Service/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Причина автоматической отмены в событии order_cancelled
+const paymentTimeoutReason = "payment_timeout"
+
+type UnpaidOrderRepository interface {
+	GetUnpaidOrdersCreatedBefore(before time.Time, statuses []string) ([]models.Order, error)
+	GetOrdersForPaymentReminder(before time.Time, statuses []string) ([]models.Order, error)
+	MarkPaymentReminderSent(id int) error
+	CancelUnpaidOrder(change models.OrderStatusChange) error
+}
+
+// UnpaidOrderPolicy задает, сколько ждать оплату заказа (PaymentWindow), за сколько до срока
+// напомнить клиенту об оплате (ReminderBefore, 0 — без напоминания) и как часто проверять заказы.
+type UnpaidOrderPolicy struct {
+	PaymentWindow  time.Duration
+	ReminderBefore time.Duration
+	CheckInterval  time.Duration
+}
+
+// Validate проверяет политику при запуске сервиса: напоминание должно приходить раньше срока оплаты.
+func (p UnpaidOrderPolicy) Validate() error {
+	if p.PaymentWindow <= 0 {
+		return errors.New("payment window must be positive")
+	}
+	if p.CheckInterval <= 0 {
+		return errors.New("check interval must be positive")
+	}
+	if p.ReminderBefore < 0 || p.ReminderBefore >= p.PaymentWindow {
+		return fmt.Errorf("payment reminder must be sent between 0 and %s before the deadline", p.PaymentWindow)
+	}
+	return nil
+}
+
+// UnpaidOrderCanceller по расписанию отменяет заказы, которые не оплачены за PaymentWindow,
+// и заранее напоминает клиентам об оплате. Резерв товара Product Service снимает по order_cancelled.
+type UnpaidOrderCanceller struct {
+	repo     UnpaidOrderRepository
+	producer *kafka.Producer
+	policy   UnpaidOrderPolicy
+}
+
+func NewUnpaidOrderCanceller(repo UnpaidOrderRepository, producer *kafka.Producer, policy UnpaidOrderPolicy) (*UnpaidOrderCanceller, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid unpaid order policy: %w", err)
+	}
+
+	return &UnpaidOrderCanceller{
+		repo:     repo,
+		producer: producer,
+		policy:   policy,
+	}, nil
+}
+
+func (uc *UnpaidOrderCanceller) Start(ctx context.Context) {
+	ticker := time.NewTicker(uc.policy.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := uc.cancelExpired(); err != nil {
+				log.Printf("Unpaid orders cancellation failed: %v", err)
+			}
+			if err := uc.remind(); err != nil {
+				log.Printf("Payment reminders failed: %v", err)
+			}
+		}
+	}
+}
+
+func (uc *UnpaidOrderCanceller) cancelExpired() error {
+	statuses := models.StatusesAllowing(models.OrderStatusCancelled, models.RoleSystem)
+	orders, err := uc.repo.GetUnpaidOrdersCreatedBefore(time.Now().Add(-uc.policy.PaymentWindow), statuses)
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		if err := order.CheckTransition(models.OrderStatusCancelled, models.RoleSystem); err != nil {
+			continue
+		}
+
+		// Если статус успели изменить или заказ оплатили, заказ пересмотрим на следующей проверке
+		err := uc.repo.CancelUnpaidOrder(models.OrderStatusChange{
+			OrderID:   order.ID,
+			OldStatus: order.Status,
+			NewStatus: models.OrderStatusCancelled,
+			ActorRole: models.RoleSystem,
+			Reason:    paymentTimeoutReason,
+		})
+		if errors.Is(err, repository.ErrOrderStatusChanged) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to cancel unpaid order %d: %v", order.ID, err)
+			continue
+		}
+
+		order.Status = models.OrderStatusCancelled
+		orderEvent := models.NewOrderEvent("order_cancelled", order)
+		orderEvent.Reason = paymentTimeoutReason
+		uc.publish(orderEvent)
+
+		log.Printf("Order %d cancelled: not paid within %s", order.ID, uc.policy.PaymentWindow)
+	}
+	return nil
+}
+
+func (uc *UnpaidOrderCanceller) remind() error {
+	if uc.policy.ReminderBefore == 0 {
+		return nil
+	}
+
+	// Заказам в payment_failed напоминать незачем: неудавшийся платеж нельзя провести повторно
+	statuses := []string{models.OrderStatusPending, models.OrderStatusConfirmed}
+	before := time.Now().Add(uc.policy.ReminderBefore - uc.policy.PaymentWindow)
+	orders, err := uc.repo.GetOrdersForPaymentReminder(before, statuses)
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		deadline := order.CreatedAt.Add(uc.policy.PaymentWindow)
+		orderEvent := models.NewOrderEvent("order_payment_reminder", order)
+		orderEvent.PaymentDeadline = &deadline
+
+		// Если событие не ушло, напоминание не отмечаем, чтобы повторить отправку на следующей проверке
+		if !uc.publish(orderEvent) {
+			continue
+		}
+		if err := uc.repo.MarkPaymentReminderSent(order.ID); err != nil {
+			log.Printf("Failed to mark payment reminder for order %d: %v", order.ID, err)
+		}
+	}
+	return nil
+}
+
+func (uc *UnpaidOrderCanceller) publish(orderEvent models.OrderEvent) bool {
+	if uc.producer == nil {
+		return false
+	}
+
+	if err := uc.producer.PublishMessage("order-events", orderEvent); err != nil {
+		log.Printf("Failed to publish %s event for order %d: %v", orderEvent.EventType, orderEvent.OrderID, err)
+		return false
+	}
+	return true
+}
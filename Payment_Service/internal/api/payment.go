@@ -33,7 +33,8 @@ func (api *api) CreatePaymentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if request.Amount <= 0 {
+	request.Amount = request.Amount.Normalize()
+	if err := request.Amount.Validate(); err != nil || request.Amount.IsZero() {
 		http.Error(w, "Invalid amount", http.StatusBadRequest)
 		return
 	}
@@ -99,7 +99,7 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 			}
 
 			switch eventType {
-			case "order_created", "order_status_updated":
+			case "order_created", "order_status_updated", "order_cancelled":
 				var orderEvent models.OrderEvent
 				if err := json.Unmarshal(message.Value, &orderEvent); err == nil {
 					if err := h.handler.HandleOrderEvent(orderEvent); err != nil {
@@ -3,26 +3,53 @@ package models
 import "time"
 
 type PaymentEvent struct {
-	EventType   string    `json:"event_type"`
-	PaymentID   string    `json:"payment_id"`
-	OrderID     int       `json:"order_id"`
-	ClientID    int       `json:"client_id"`
-	Amount      float64   `json:"amount"`
-	Status      string    `json:"status"`
-	Timestamp   time.Time `json:"timestamp"`
+	EventType string    `json:"event_type"`
+	PaymentID string    `json:"payment_id"`
+	OrderID   int       `json:"order_id"`
+	ClientID  int       `json:"client_id"`
+	Amount    Money     `json:"amount"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 type OrderEvent struct {
-	EventType   string    `json:"event_type"`
-	OrderID     int       `json:"order_id"`
-	ProductName string    `json:"product_name"`
-	ProductID   int       `json:"product_id"`
-	SupplierID  int       `json:"supplier_id"`
-	ClientID    int       `json:"client_id"`
-	Status      string    `json:"status,omitempty"`
-	Amount      float64   `json:"amount,omitempty"`
-	PaymentID   string    `json:"payment_id,omitempty"`
-	Timestamp   time.Time `json:"timestamp"`
+	EventType   string      `json:"event_type"`
+	OrderID     int         `json:"order_id"`
+	ProductName string      `json:"product_name"`
+	ProductID   int         `json:"product_id"`
+	SupplierID  int         `json:"supplier_id"`
+	ClientID    int         `json:"client_id"`
+	Status      string      `json:"status,omitempty"`
+	Amount      Money       `json:"amount"`
+	PaymentID   string      `json:"payment_id,omitempty"`
+	Items       []OrderItem `json:"items,omitempty"`
+	Timestamp   time.Time   `json:"timestamp"`
+}
+
+type OrderItem struct {
+	ProductID   int    `json:"product_id"`
+	VariantID   int    `json:"variant_id,omitempty"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   Money  `json:"unit_price"`
+	LineTotal   Money  `json:"line_total"`
+}
+
+// ItemsTotal возвращает сумму позиций заказа. Для событий без items возвращается Amount.
+func (e OrderEvent) ItemsTotal() (Money, error) {
+	if len(e.Items) == 0 {
+		return e.Amount, nil
+	}
+
+	total := NewMoney(0, e.Amount.Currency)
+	for _, item := range e.Items {
+		sum, err := total.Add(item.LineTotal)
+		if err != nil {
+			return Money{}, err
+		}
+		total = sum
+	}
+	return total, nil
 }
 
 type UserInfo struct {
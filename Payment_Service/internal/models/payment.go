@@ -8,6 +8,7 @@ const (
 	PaymentStatusPending   PaymentStatus = "pending"
 	PaymentStatusCompleted PaymentStatus = "completed"
 	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusCancelled PaymentStatus = "cancelled"
 )
 
 type PaymentMethod string
@@ -20,7 +21,7 @@ type Payment struct {
 	ID            string        `json:"id"`
 	OrderID       int           `json:"order_id"`
 	ClientID      int           `json:"client_id"`
-	Amount        float64       `json:"amount"`
+	Amount        Money         `json:"amount"`
 	Status        PaymentStatus `json:"status"`
 	PaymentMethod PaymentMethod `json:"payment_method"`
 	CreatedAt     time.Time     `json:"created_at"`
@@ -31,7 +32,7 @@ type Payment struct {
 
 type CreatePaymentRequest struct {
 	OrderID       int           `json:"order_id"`
-	Amount        float64       `json:"amount"`
+	Amount        Money         `json:"amount"`
 	PaymentMethod PaymentMethod `json:"payment_method"`
 }
 
@@ -48,7 +49,7 @@ type ProcessPaymentRequest struct {
 type PaymentResponse struct {
 	ID            string        `json:"id"`
 	OrderID       int           `json:"order_id"`
-	Amount        float64       `json:"amount"`
+	Amount        Money         `json:"amount"`
 	Status        PaymentStatus `json:"status"`
 	PaymentMethod PaymentMethod `json:"payment_method"`
 	CreatedAt     time.Time     `json:"created_at"`
@@ -9,15 +9,16 @@ import (
 
 func (r *PGRepo) CreatePayment(payment models.Payment) error {
 	query := `
-		INSERT INTO payments (id, order_id, client_id, amount, status, payment_method, transaction_id, failure_reason, created_at, completed_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO payments (id, order_id, client_id, amount_minor, currency, status, payment_method, transaction_id, failure_reason, created_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	_, err := r.db.Exec(query,
 		payment.ID,
 		payment.OrderID,
 		payment.ClientID,
-		payment.Amount,
+		payment.Amount.Amount,
+		payment.Amount.Currency,
 		payment.Status,
 		payment.PaymentMethod,
 		payment.TransactionID,
@@ -31,7 +32,7 @@ func (r *PGRepo) CreatePayment(payment models.Payment) error {
 
 func (r *PGRepo) GetPaymentByID(paymentID string) (*models.Payment, error) {
 	query := `
-		SELECT id, order_id, client_id, amount, status, payment_method, transaction_id, failure_reason, created_at, completed_at
+		SELECT id, order_id, client_id, amount_minor, currency, status, payment_method, transaction_id, failure_reason, created_at, completed_at
 		FROM payments
 		WHERE id = $1
 	`
@@ -43,7 +44,8 @@ func (r *PGRepo) GetPaymentByID(paymentID string) (*models.Payment, error) {
 		&payment.ID,
 		&payment.OrderID,
 		&payment.ClientID,
-		&payment.Amount,
+		&payment.Amount.Amount,
+		&payment.Amount.Currency,
 		&payment.Status,
 		&payment.PaymentMethod,
 		&payment.TransactionID,
@@ -68,7 +70,7 @@ func (r *PGRepo) GetPaymentByID(paymentID string) (*models.Payment, error) {
 
 func (r *PGRepo) GetPaymentsByClientID(clientID int) ([]models.Payment, error) {
 	query := `
-		SELECT id, order_id, client_id, amount, status, payment_method, transaction_id, failure_reason, created_at, completed_at
+		SELECT id, order_id, client_id, amount_minor, currency, status, payment_method, transaction_id, failure_reason, created_at, completed_at
 		FROM payments
 		WHERE client_id = $1
 		ORDER BY created_at DESC
@@ -89,7 +91,8 @@ func (r *PGRepo) GetPaymentsByClientID(clientID int) ([]models.Payment, error) {
 			&payment.ID,
 			&payment.OrderID,
 			&payment.ClientID,
-			&payment.Amount,
+			&payment.Amount.Amount,
+			&payment.Amount.Currency,
 			&payment.Status,
 			&payment.PaymentMethod,
 			&payment.TransactionID,
@@ -113,7 +116,7 @@ func (r *PGRepo) GetPaymentsByClientID(clientID int) ([]models.Payment, error) {
 
 func (r *PGRepo) GetPaymentByOrderID(orderID int) (*models.Payment, error) {
 	query := `
-		SELECT id, order_id, client_id, amount, status, payment_method, transaction_id, failure_reason, created_at, completed_at
+		SELECT id, order_id, client_id, amount_minor, currency, status, payment_method, transaction_id, failure_reason, created_at, completed_at
 		FROM payments
 		WHERE order_id = $1
 		ORDER BY created_at DESC
@@ -127,7 +130,8 @@ func (r *PGRepo) GetPaymentByOrderID(orderID int) (*models.Payment, error) {
 		&payment.ID,
 		&payment.OrderID,
 		&payment.ClientID,
-		&payment.Amount,
+		&payment.Amount.Amount,
+		&payment.Amount.Currency,
 		&payment.Status,
 		&payment.PaymentMethod,
 		&payment.TransactionID,
@@ -166,3 +170,18 @@ func (r *PGRepo) UpdatePaymentStatus(paymentID string, status models.PaymentStat
 	_, err := r.db.Exec(query, status, transactionID, failureReason, completedAt, paymentID)
 	return err
 }
+
+// CancelPendingPayment отменяет платеж заказа, если он еще ожидает оплаты, и сообщает, был ли он отменен.
+func (r *PGRepo) CancelPendingPayment(orderID int, reason string) (bool, error) {
+	result, err := r.db.Exec(`UPDATE payments SET status = $1, failure_reason = $2 WHERE order_id = $3 AND status = $4`,
+		models.PaymentStatusCancelled, reason, orderID, models.PaymentStatusPending)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
@@ -39,7 +39,8 @@ func createPaymentsTable(db *sql.DB) error {
 		id VARCHAR(36) PRIMARY KEY,
 		order_id INTEGER NOT NULL,
 		client_id INTEGER NOT NULL,
-		amount DECIMAL(10,2) NOT NULL,
+		amount_minor BIGINT NOT NULL,
+		currency CHAR(3) NOT NULL DEFAULT 'RUB',
 		status VARCHAR(20) NOT NULL DEFAULT 'pending',
 		payment_method VARCHAR(20) NOT NULL,
 		transaction_id VARCHAR(100),
@@ -48,6 +49,18 @@ func createPaymentsTable(db *sql.DB) error {
 		completed_at TIMESTAMP
 	);
 	
+	-- Старые суммы хранились в DECIMAL(10,2) в колонке amount, переводим их в копейки
+	DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'payments' AND column_name = 'amount') THEN
+			ALTER TABLE payments ADD COLUMN IF NOT EXISTS amount_minor BIGINT NOT NULL DEFAULT 0;
+			UPDATE payments SET amount_minor = ROUND(amount * 100)::BIGINT;
+			ALTER TABLE payments DROP COLUMN amount;
+		END IF;
+	END $$;
+
+	ALTER TABLE payments ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
 	CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments(order_id);
 	CREATE INDEX IF NOT EXISTS idx_payments_client_id ON payments(client_id);
 	CREATE INDEX IF NOT EXISTS idx_payments_status ON payments(status);
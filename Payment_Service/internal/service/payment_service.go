@@ -21,6 +21,7 @@ type PaymentRepository interface {
 	GetPaymentsByClientID(clientID int) ([]models.Payment, error)
 	GetPaymentByOrderID(orderID int) (*models.Payment, error)
 	UpdatePaymentStatus(paymentID string, status models.PaymentStatus, transactionID, failureReason string) error
+	CancelPendingPayment(orderID int, reason string) (bool, error)
 }
 
 func NewPaymentService(repo PaymentRepository, producer *kafka.Producer) *PaymentService {
@@ -36,6 +37,12 @@ func (ps *PaymentService) HandleOrderEvent(event models.OrderEvent) error {
 	switch event.EventType {
 	case "order_created":
 		return ps.handleOrderCreated(event)
+	case "order_cancelled":
+		return ps.handleOrderCancelled(event)
+	case "order_status_updated":
+		if event.Status == "cancelled" {
+			return ps.handleOrderCancelled(event)
+		}
 	default:
 		log.Printf("Unknown event type: %s", event.EventType)
 	}
@@ -44,6 +51,15 @@ func (ps *PaymentService) HandleOrderEvent(event models.OrderEvent) error {
 }
 
 func (ps *PaymentService) handleOrderCreated(event models.OrderEvent) error {
+	// Платеж создается на сумму заказа, поэтому она должна совпадать с суммой позиций
+	itemsTotal, err := event.ItemsTotal()
+	if err != nil {
+		return fmt.Errorf("invalid items of order %d: %w", event.OrderID, err)
+	}
+	if itemsTotal != event.Amount {
+		return fmt.Errorf("order %d amount %s does not match items total %s", event.OrderID, event.Amount, itemsTotal)
+	}
+
 	paymentID, _ := uuid.GenerateUUID()
 
 	payment := models.Payment{
@@ -78,6 +94,19 @@ func (ps *PaymentService) handleOrderCreated(event models.OrderEvent) error {
 	return nil
 }
 
+// handleOrderCancelled отменяет еще не проведенный платеж отмененного заказа, чтобы его нельзя было оплатить.
+func (ps *PaymentService) handleOrderCancelled(event models.OrderEvent) error {
+	cancelled, err := ps.repo.CancelPendingPayment(event.OrderID, "Order cancelled")
+	if err != nil {
+		return fmt.Errorf("failed to cancel payment for order %d: %w", event.OrderID, err)
+	}
+
+	if cancelled {
+		log.Printf("Payment for cancelled order %d cancelled", event.OrderID)
+	}
+	return nil
+}
+
 func (ps *PaymentService) CreatePayment(request models.CreatePaymentRequest, clientID int) (*models.PaymentResponse, error) {
 	existingPayment, err := ps.repo.GetPaymentByOrderID(request.OrderID)
 	if err == nil && existingPayment != nil {
@@ -2,9 +2,20 @@ package main
 
 import (
 	"Product_Service/internal/api"
+	"Product_Service/internal/cache"
+	"Product_Service/internal/kafka"
 	"Product_Service/internal/repository"
-	"github.com/gorilla/mux"
+	"Product_Service/internal/service"
+	"Product_Service/internal/storage"
+	"Product_Service/internal/users"
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
 )
 
 func main() {
@@ -12,7 +23,67 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	api := api.NewAPI(mux.NewRouter(), db)
+
+	// Задания импорта выполняются в памяти процесса и не переживают перезапуск
+	if count, err := db.FailUnfinishedImportJobs("import interrupted by service restart"); err != nil {
+		log.Printf("Failed to close unfinished import jobs: %v", err)
+	} else if count > 0 {
+		log.Printf("Marked %d unfinished import jobs as failed", count)
+	}
+
+	brokers := []string{"localhost:9092"}
+	topics := []string{"order-events"}
+
+	kafkaProducer, err := kafka.NewProducer(brokers)
+	if err != nil {
+		log.Printf("Failed to create Kafka producer: %v", err)
+	}
+
+	catalogCache := cache.NewMemoryCache(10000)
+
+	inventoryService := service.NewInventoryService(db, kafkaProducer)
+	reviewService := service.NewReviewService(db)
+	recommendationService := service.NewRecommendationService(db)
+	cacheInvalidator := service.NewCacheInvalidator(catalogCache)
+	consumer := kafka.NewConsumer(brokers, topics, inventoryService, reviewService, recommendationService, cacheInvalidator)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		if err := consumer.Start(ctx); err != nil {
+			log.Fatalf("Error starting Kafka consumer: %v", err)
+		}
+	}()
+
+	wishlistWatcher := service.NewWishlistWatcher(db, kafkaProducer, time.Minute)
+	go wishlistWatcher.Start(ctx)
+
+	imageStorage, err := storage.NewLocalStorage("./uploads", "http://localhost:8082/images")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	userClient := users.NewClient("http://localhost:8081")
+
+	api := api.NewAPI(mux.NewRouter(), db, imageStorage, kafkaProducer, userClient, catalogCache)
 	api.Handle()
-	log.Fatal(api.ListenAndServe("localhost:8082"))
+
+	go func() {
+		log.Println("Product Service started on :8082")
+		if err := api.ListenAndServe("localhost:8082"); err != nil {
+			log.Fatalf("Error starting HTTP server: %v", err)
+		}
+	}()
+
+	sigterm := make(chan os.Signal, 1)
+	signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
+	<-sigterm
+
+	log.Println("Shutting down Product Service...")
+	cancel()
+	if kafkaProducer != nil {
+		kafkaProducer.Close()
+	}
+	log.Println("Product Service stopped")
 }
@@ -1,18 +1,36 @@
 package api
 
 import (
+	"Product_Service/internal/cache"
+	"Product_Service/internal/kafka"
 	"Product_Service/internal/repository"
+	"Product_Service/internal/service"
+	"Product_Service/internal/storage"
+	"Product_Service/internal/users"
 	"github.com/gorilla/mux"
 	"net/http"
 )
 
 type api struct {
-	r  *mux.Router
-	db *repository.PGRepo
+	r        *mux.Router
+	db       *repository.PGRepo
+	storage  storage.Storage
+	producer *kafka.Producer
+	users    *users.Client
+	cache    cache.Cache
+
+	importService *service.ImportService
+}
+
+// fileServer реализуют хранилища, которые сами раздают файлы (например, локальное)
+type fileServer interface {
+	Handler() http.Handler
 }
 
-func NewAPI(r *mux.Router, db *repository.PGRepo) *api {
-	return &api{r: r, db: db}
+func NewAPI(r *mux.Router, db *repository.PGRepo, storage storage.Storage, producer *kafka.Producer, users *users.Client, cache cache.Cache) *api {
+	api := &api{r: r, db: db, storage: storage, producer: producer, users: users, cache: cache}
+	api.importService = service.NewImportService(db, api.onProductImported)
+	return api
 }
 
 func (api *api) Handle() {
@@ -20,6 +38,68 @@ func (api *api) Handle() {
 	api.r.HandleFunc("/api/product/delete", api.DeleteProductHandler).Queries("id", "{id}")
 	api.r.HandleFunc("/api/product/client", api.GetAllProductsForClientHandler)
 	api.r.HandleFunc("/api/product/supplier", api.GetAllProductsForSupplierHandler)
+	api.r.HandleFunc("/api/product/search", api.SearchProductsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/import", api.ImportProductsHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/import/{job_id:[0-9]+}", api.GetImportJobHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/supplier/export", api.ExportProductsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/supplier/questions", api.GetSupplierQuestionsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/{id:[0-9]+}", api.GetProductHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/{id:[0-9]+}", api.UpdateProductHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/product/{id}/submit", api.SubmitProductHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/restore", api.RestoreProductHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/categories", api.SetProductCategoriesHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/product/{id}/stock", api.UpdateProductStockHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/product/{id}/images", api.UploadProductImageHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/images/order", api.ReorderProductImagesHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/product/{id}/images/{image_id}", api.DeleteProductImageHandler).Methods(http.MethodDelete)
+	api.r.HandleFunc("/api/product/{id}/options", api.SetProductOptionsHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/product/{id}/variants", api.GetProductVariantsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/{id}/variants", api.CreateVariantHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/variants/{variant_id}", api.UpdateVariantHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/product/{id}/variants/{variant_id}", api.DeleteVariantHandler).Methods(http.MethodDelete)
+	api.r.HandleFunc("/api/product/{id}/recommendations", api.GetRecommendationsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/{id}/price-history", api.GetPriceHistoryHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/{id}/promotions", api.GetProductPromotionsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/{id}/promotions", api.CreatePromotionHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/promotions/{promotion_id}", api.DeletePromotionHandler).Methods(http.MethodDelete)
+	api.r.HandleFunc("/api/product/{id}/translations", api.GetProductTranslationsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/{id}/translations/{locale}", api.SetProductTranslationHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/product/{id}/translations/{locale}", api.DeleteProductTranslationHandler).Methods(http.MethodDelete)
+	api.r.HandleFunc("/api/product/{id}/reviews", api.GetProductReviewsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/{id}/reviews", api.CreateReviewHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/reviews/{review_id}/photos", api.UploadReviewPhotoHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/reviews/{review_id}/reply", api.ReplyToReviewHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/reviews/{review_id}/flag", api.FlagReviewHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/questions", api.GetProductQuestionsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/product/{id}/questions", api.CreateQuestionHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/product/{id}/questions/{question_id}/answer", api.AnswerQuestionHandler).Methods(http.MethodPost)
+
+	api.r.HandleFunc("/api/supplier/{id:[0-9]+}/storefront", api.GetSupplierStorefrontHandler).Methods(http.MethodGet)
+
+	api.r.HandleFunc("/api/wishlists", api.GetWishlistsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/wishlists", api.CreateWishlistHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/wishlists/{id:[0-9]+}", api.DeleteWishlistHandler).Methods(http.MethodDelete)
+	api.r.HandleFunc("/api/wishlists/{id:[0-9]+}/items", api.AddWishlistItemHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/wishlists/{id:[0-9]+}/items/{product_id:[0-9]+}", api.RemoveWishlistItemHandler).Methods(http.MethodDelete)
+
+	api.r.HandleFunc("/api/moderation/products", api.GetModerationQueueHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/moderation/products/{id:[0-9]+}", api.ModerateProductHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/moderation/banned-words", api.GetBannedWordsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/moderation/banned-words", api.CreateBannedWordHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/moderation/banned-words/{id:[0-9]+}", api.DeleteBannedWordHandler).Methods(http.MethodDelete)
+
+	api.r.HandleFunc("/api/reviews/flagged", api.GetFlaggedReviewsHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/reviews/{review_id}/moderation", api.ModerateReviewHandler).Methods(http.MethodPut)
+
+	api.r.HandleFunc("/api/categories", api.GetCategoriesHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/category/create", api.CreateCategoryHandler).Methods(http.MethodPost)
+	api.r.HandleFunc("/api/category/{id}", api.GetCategoryHandler).Methods(http.MethodGet)
+	api.r.HandleFunc("/api/category/{id}", api.UpdateCategoryHandler).Methods(http.MethodPut)
+	api.r.HandleFunc("/api/category/{id}", api.DeleteCategoryHandler).Methods(http.MethodDelete)
+
+	if fs, ok := api.storage.(fileServer); ok {
+		api.r.PathPrefix("/images/").Handler(http.StripPrefix("/images/", fs.Handler()))
+	}
 }
 
 func (api *api) ListenAndServe(addr string) error {
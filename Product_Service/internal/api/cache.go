@@ -0,0 +1,79 @@
+package api
+
+import (
+	"Product_Service/internal/cache"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Время жизни ответов каталога в кэше. Изменения товаров сбрасывают кэш сразу,
+// TTL ограничивает устаревание того, что меняется без записи в сервисе (начало акции).
+const (
+	productCacheTTL = 30 * time.Second
+	listCacheTTL    = 15 * time.Second
+)
+
+// cachedJSON отдает ответ из кэша по ключу key. Возвращает false, если ответа в кэше нет.
+func (api *api) cachedJSON(w http.ResponseWriter, r *http.Request, key string) bool {
+	if api.cache == nil {
+		return false
+	}
+
+	body, ok := api.cache.Get(key)
+	if !ok {
+		return false
+	}
+
+	writeJSONWithETag(w, r, body)
+	return true
+}
+
+// cacheJSON кодирует ответ, сохраняет его в кэше и отправляет клиенту.
+func (api *api) cacheJSON(w http.ResponseWriter, r *http.Request, key string, ttl time.Duration, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+
+	if api.cache != nil && key != "" {
+		api.cache.Set(key, body, ttl)
+	}
+	writeJSONWithETag(w, r, body)
+}
+
+// writeJSONWithETag отправляет JSON с ETag по содержимому; если у клиента та же версия,
+// отвечает 304 Not Modified без тела.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidateProduct сбрасывает кэш после любого изменения товара.
+func (api *api) invalidateProduct(productID int) {
+	cache.InvalidateProduct(api.cache, productID)
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"Product_Service/internal/cache"
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+func (api *api) CreateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "admin" {
+		http.Error(w, "Only admins can manage categories", http.StatusForbidden)
+		return
+	}
+
+	var request models.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		http.Error(w, "Category name is required", http.StatusBadRequest)
+		return
+	}
+
+	categoryID, err := api.db.CreateCategory(request)
+	if err != nil {
+		http.Error(w, "Error creating category", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.Category{ID: categoryID, Name: request.Name, ParentID: request.ParentID})
+}
+
+func (api *api) UpdateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "admin" {
+		http.Error(w, "Only admins can manage categories", http.StatusForbidden)
+		return
+	}
+
+	categoryID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid category id", http.StatusBadRequest)
+		return
+	}
+
+	var request models.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		http.Error(w, "Category name is required", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.UpdateCategory(categoryID, request)
+	switch {
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, repository.ErrCategoryCycle):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Error updating category", http.StatusBadRequest)
+		return
+	}
+
+	// Перенос категории меняет выдачу каталога по фильтру category_id
+	cache.InvalidateAll(api.cache)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.Category{ID: categoryID, Name: request.Name, ParentID: request.ParentID})
+}
+
+func (api *api) DeleteCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "admin" {
+		http.Error(w, "Only admins can manage categories", http.StatusForbidden)
+		return
+	}
+
+	categoryID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid category id", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.DeleteCategory(categoryID)
+	switch {
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, repository.ErrCategoryInUse):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Error deleting category", http.StatusInternalServerError)
+		return
+	}
+
+	cache.InvalidateAll(api.cache)
+}
+
+func (api *api) GetCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	_, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	categories, err := api.db.GetAllCategories()
+	if err != nil {
+		http.Error(w, "Error getting categories", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildCategoryTree(categories))
+}
+
+func (api *api) GetCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	_, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	categoryID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid category id", http.StatusBadRequest)
+		return
+	}
+
+	categories, err := api.db.GetAllCategories()
+	if err != nil {
+		http.Error(w, "Error getting categories", http.StatusInternalServerError)
+		return
+	}
+
+	buildCategoryTree(categories)
+	for _, category := range categories {
+		if category.ID == categoryID {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(category)
+			return
+		}
+	}
+
+	http.Error(w, "Category not found", http.StatusNotFound)
+}
+
+func (api *api) SetProductCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "supplier" {
+		http.Error(w, "Only suppliers can change product categories", http.StatusForbidden)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	product, err := api.db.GetProductByID(productID)
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	if product.UserID != user.ID {
+		http.Error(w, "You can only change your own products", http.StatusForbidden)
+		return
+	}
+
+	var request models.ProductCategoriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := api.db.SetProductCategories(productID, request.CategoryIDs); err != nil {
+		http.Error(w, "Error setting product categories", http.StatusBadRequest)
+		return
+	}
+
+	product.CategoryIDs = request.CategoryIDs
+	api.publishProductSnapshot("product_updated", productID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// buildCategoryTree связывает категории из плоского списка в дерево и возвращает корневые.
+func buildCategoryTree(categories []*models.Category) []*models.Category {
+	byID := make(map[int]*models.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	roots := []*models.Category{}
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		if parent, ok := byID[*category.ParentID]; ok {
+			parent.Children = append(parent.Children, category)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"Product_Service/internal/models"
+	"log"
+	"strconv"
+	"time"
+)
+
+const productEventsTopic = "product-events"
+
+// publishProductEvent отправляет актуальный снимок товара в product-events.
+// Ключ сообщения — id товара, поэтому события одного товара читаются по порядку.
+func (api *api) publishProductEvent(eventType string, product models.Product, oldPrice *models.Money) {
+	api.sendProductEvent(models.ProductEvent{
+		EventType:  eventType,
+		ProductID:  product.ID,
+		SupplierID: product.UserID,
+		Product:    product,
+		OldPrice:   oldPrice,
+		Timestamp:  time.Now(),
+	})
+}
+
+// publishModerationEvent сообщает о решении модератора; Notification Service отправляет поставщику письмо.
+func (api *api) publishModerationEvent(eventType string, product models.Product, reason string) {
+	api.sendProductEvent(models.ProductEvent{
+		EventType:  eventType,
+		ProductID:  product.ID,
+		SupplierID: product.UserID,
+		Product:    product,
+		Reason:     reason,
+		Timestamp:  time.Now(),
+	})
+}
+
+// sendProductEvent публикует событие товара. Каждое изменение товара проходит через события,
+// поэтому здесь же сбрасывается кэш каталога.
+func (api *api) sendProductEvent(event models.ProductEvent) {
+	api.invalidateProduct(event.ProductID)
+
+	if api.producer == nil {
+		return
+	}
+
+	if err := api.producer.PublishKeyedMessage(productEventsTopic, strconv.Itoa(event.ProductID), event); err != nil {
+		log.Printf("Failed to publish %s event: %v", event.EventType, err)
+	}
+}
+
+// publishProductSnapshot перечитывает товар из базы и публикует его снимок.
+func (api *api) publishProductSnapshot(eventType string, productID int) {
+	api.invalidateProduct(productID)
+
+	if api.producer == nil {
+		return
+	}
+
+	product, err := api.db.GetProductByID(productID)
+	if err != nil {
+		log.Printf("Failed to load product %d for %s event: %v", productID, eventType, err)
+		return
+	}
+	api.publishProductEvent(eventType, product, nil)
+}
@@ -0,0 +1,318 @@
+package api
+
+import (
+	"Product_Service/internal/imaging"
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	maxImageSize      = 5 << 20
+	maxImageDimension = 5000
+	thumbnailSize     = 320
+)
+
+// Допустимые типы изображений и расширения файлов для них
+var allowedImageTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+type uploadedImage struct {
+	data        []byte
+	contentType string
+	extension   string
+	image       image.Image
+	format      string
+}
+
+func (api *api) UploadProductImageHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	upload, ok := readImageUpload(w, r, "image")
+	if !ok {
+		return
+	}
+
+	thumbnail, thumbnailType, thumbnailExtension, err := encodeThumbnail(upload.image, upload.format)
+	if err != nil {
+		http.Error(w, "Error creating thumbnail", http.StatusInternalServerError)
+		return
+	}
+
+	name, err := randomName()
+	if err != nil {
+		http.Error(w, "Error saving image", http.StatusInternalServerError)
+		return
+	}
+
+	productImage := models.ProductImage{
+		ProductID:    product.ID,
+		StorageKey:   fmt.Sprintf("products/%d/%s.%s", product.ID, name, upload.extension),
+		ThumbnailKey: fmt.Sprintf("products/%d/%s_thumb.%s", product.ID, name, thumbnailExtension),
+		ContentType:  upload.contentType,
+		SizeBytes:    int64(len(upload.data)),
+		Width:        upload.image.Bounds().Dx(),
+		Height:       upload.image.Bounds().Dy(),
+	}
+
+	ctx := r.Context()
+	if err := api.storage.Put(ctx, productImage.StorageKey, bytes.NewReader(upload.data), upload.contentType); err != nil {
+		http.Error(w, "Error saving image", http.StatusInternalServerError)
+		return
+	}
+	if err := api.storage.Put(ctx, productImage.ThumbnailKey, bytes.NewReader(thumbnail), thumbnailType); err != nil {
+		api.deleteImageFiles(productImage)
+		http.Error(w, "Error saving image", http.StatusInternalServerError)
+		return
+	}
+
+	productImage, err = api.db.CreateProductImage(productImage)
+	if err != nil {
+		api.deleteImageFiles(productImage)
+		if errors.Is(err, repository.ErrTooManyImages) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error saving image", http.StatusInternalServerError)
+		return
+	}
+	api.publishProductSnapshot("product_updated", product.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(api.imageURLs([]models.ProductImage{productImage})[0])
+}
+
+func (api *api) ReorderProductImagesHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.ReorderImagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := api.db.ReorderProductImages(product.ID, request.ImageIDs)
+	if errors.Is(err, repository.ErrImageOrderMismatch) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error reordering images", http.StatusInternalServerError)
+		return
+	}
+	api.invalidateProduct(product.ID)
+
+	images, err := api.db.GetProductImages(product.ID)
+	if err != nil {
+		http.Error(w, "Error getting images", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.imageURLs(images))
+}
+
+func (api *api) DeleteProductImageHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	imageID, err := strconv.Atoi(mux.Vars(r)["image_id"])
+	if err != nil {
+		http.Error(w, "Invalid image id", http.StatusBadRequest)
+		return
+	}
+
+	productImage, err := api.db.DeleteProductImage(product.ID, imageID)
+	if errors.Is(err, repository.ErrImageNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error deleting image", http.StatusInternalServerError)
+		return
+	}
+	api.invalidateProduct(product.ID)
+
+	api.deleteImageFiles(productImage)
+}
+
+// ownProductFromRequest проверяет, что запрос пришел от поставщика-владельца товара {id}.
+// При ошибке ответ уже записан в w.
+func (api *api) ownProductFromRequest(w http.ResponseWriter, r *http.Request) (models.Product, bool) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return models.Product{}, false
+	}
+
+	if user.Role != "supplier" {
+		http.Error(w, "Only suppliers can manage products", http.StatusForbidden)
+		return models.Product{}, false
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return models.Product{}, false
+	}
+
+	product, err := api.db.GetProductByID(productID)
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return models.Product{}, false
+	}
+
+	if product.UserID != user.ID {
+		http.Error(w, "You can only manage your own products", http.StatusForbidden)
+		return models.Product{}, false
+	}
+
+	return product, true
+}
+
+// attachImages подгружает изображения для товаров одним запросом.
+func (api *api) attachImages(products []models.Product) error {
+	ids := make([]int, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	images, err := api.db.GetImagesForProducts(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].Images = api.imageURLs(images[products[i].ID])
+	}
+	return nil
+}
+
+func (api *api) imageURLs(images []models.ProductImage) []models.ProductImage {
+	for i := range images {
+		images[i].URL = api.storage.URL(images[i].StorageKey)
+		images[i].ThumbnailURL = api.storage.URL(images[i].ThumbnailKey)
+	}
+	return images
+}
+
+func (api *api) deleteImageFiles(productImage models.ProductImage) {
+	for _, key := range []string{productImage.StorageKey, productImage.ThumbnailKey} {
+		if err := api.storage.Delete(context.Background(), key); err != nil {
+			log.Printf("Failed to delete image file %s: %v", key, err)
+		}
+	}
+}
+
+// readImageUpload читает изображение из multipart-поля field и проверяет его размер и тип.
+// При ошибке ответ уже записан в w.
+func readImageUpload(w http.ResponseWriter, r *http.Request, field string) (uploadedImage, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageSize+1<<20)
+	if err := r.ParseMultipartForm(maxImageSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Image is too large", http.StatusRequestEntityTooLarge)
+			return uploadedImage{}, false
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return uploadedImage{}, false
+	}
+
+	file, _, err := r.FormFile(field)
+	if err != nil {
+		http.Error(w, "Image file is required", http.StatusBadRequest)
+		return uploadedImage{}, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImageSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return uploadedImage{}, false
+	}
+	if len(data) > maxImageSize {
+		http.Error(w, "Image is too large", http.StatusRequestEntityTooLarge)
+		return uploadedImage{}, false
+	}
+
+	// Тип определяем по содержимому, а не по заголовку от клиента
+	contentType := http.DetectContentType(data)
+	extension, ok := allowedImageTypes[contentType]
+	if !ok {
+		http.Error(w, "Unsupported image type: "+contentType, http.StatusUnsupportedMediaType)
+		return uploadedImage{}, false
+	}
+
+	// Размеры проверяем по заголовку до декодирования: маленький файл может объявить огромную картинку
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		http.Error(w, "Invalid image", http.StatusBadRequest)
+		return uploadedImage{}, false
+	}
+	if config.Width > maxImageDimension || config.Height > maxImageDimension {
+		http.Error(w, fmt.Sprintf("Image must be at most %dx%d pixels", maxImageDimension, maxImageDimension), http.StatusRequestEntityTooLarge)
+		return uploadedImage{}, false
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		http.Error(w, "Invalid image", http.StatusBadRequest)
+		return uploadedImage{}, false
+	}
+
+	return uploadedImage{data: data, contentType: contentType, extension: extension, image: img, format: format}, true
+}
+
+func encodeThumbnail(img image.Image, format string) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+	thumbnail := imaging.Thumbnail(img, thumbnailSize)
+
+	// PNG и GIF могут быть прозрачными, поэтому их миниатюры сохраняем в PNG
+	if format == "png" || format == "gif" {
+		if err := png.Encode(&buf, thumbnail); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/png", "png", nil
+	}
+
+	if err := jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), "image/jpeg", "jpg", nil
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
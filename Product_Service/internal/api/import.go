@@ -0,0 +1,190 @@
+package api
+
+import (
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"Product_Service/internal/service"
+	"Product_Service/internal/spreadsheet"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const maxImportFileSize = 10 << 20
+
+const maxImportRows = 10000
+
+// ImportProductsHandler принимает CSV или XLSX с каталогом поставщика и запускает фоновое задание импорта.
+func (api *api) ImportProductsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "supplier" {
+		http.Error(w, "Only suppliers can import products", http.StatusForbidden)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize+1<<20)
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "File is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Import file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxImportFileSize {
+		http.Error(w, "File is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	format := spreadsheet.DetectFormat(data)
+	rows, err := spreadsheet.Read(format, data)
+	if err != nil {
+		http.Error(w, "Invalid "+string(format)+" file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(rows) > maxImportRows+1 {
+		http.Error(w, fmt.Sprintf("Too many rows, the limit is %d", maxImportRows), http.StatusBadRequest)
+		return
+	}
+
+	job, err := api.db.CreateImportJob(user.ID, header.Filename, string(format))
+	if err != nil {
+		http.Error(w, "Error creating import job", http.StatusInternalServerError)
+		return
+	}
+
+	if err := api.importService.Start(job, rows); err != nil {
+		http.Error(w, "Too many imports in progress, try again later", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+func (api *api) GetImportJobHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	jobID, err := strconv.Atoi(mux.Vars(r)["job_id"])
+	if err != nil {
+		http.Error(w, "Invalid import job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := api.db.GetImportJob(jobID)
+	if errors.Is(err, repository.ErrImportJobNotFound) || (err == nil && job.SupplierID != user.ID) {
+		http.Error(w, "Import job not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error getting import job", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// ExportProductsHandler выгружает каталог поставщика в тех же колонках, что принимает импорт.
+func (api *api) ExportProductsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "supplier" {
+		http.Error(w, "Only suppliers can export products", http.StatusForbidden)
+		return
+	}
+
+	format := spreadsheet.Format(r.URL.Query().Get("format"))
+	if format == "" {
+		format = spreadsheet.FormatCSV
+	}
+
+	contentTypes := map[spreadsheet.Format]string{
+		spreadsheet.FormatCSV:  "text/csv; charset=utf-8",
+		spreadsheet.FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	}
+	contentType, ok := contentTypes[format]
+	if !ok {
+		http.Error(w, spreadsheet.ErrUnsupportedFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	products, err := api.db.GetAllProductsForSupplier(user.ID)
+	if err != nil {
+		http.Error(w, "Error getting products", http.StatusBadRequest)
+		return
+	}
+
+	rows := [][]string{service.ImportColumns}
+	for _, product := range products {
+		rows = append(rows, []string{
+			strconv.Itoa(product.ID),
+			product.SKU,
+			product.Name,
+			product.Description,
+			product.Price.Decimal(),
+			product.Price.Currency,
+			strconv.Itoa(product.Stock),
+		})
+	}
+
+	filename := fmt.Sprintf("products-%s.%s", time.Now().Format("20060102"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err := spreadsheet.Write(format, w, rows); err != nil {
+		log.Printf("Failed to export products for supplier %d: %v", user.ID, err)
+	}
+}
+
+// onProductImported публикует в product-events те же события, что и ручное создание или изменение товара.
+func (api *api) onProductImported(productID int, created bool, oldPrice *models.Money) {
+	if created {
+		api.publishProductSnapshot("product_created", productID)
+		return
+	}
+
+	product, err := api.db.GetProductByID(productID)
+	if err != nil {
+		log.Printf("Failed to load imported product %d: %v", productID, err)
+		return
+	}
+
+	api.publishProductEvent("product_updated", product, nil)
+	if oldPrice != nil && *oldPrice != product.Price {
+		api.publishProductEvent("product_price_changed", product, oldPrice)
+	}
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// adminFromRequest проверяет токен и то, что запрос сделал администратор.
+func (api *api) adminFromRequest(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+
+	if user.Role != "admin" {
+		http.Error(w, "Only admins can moderate products", http.StatusForbidden)
+		return nil, false
+	}
+	return user, true
+}
+
+// SubmitProductHandler отправляет черновик или отклоненный товар на модерацию. Текст товара
+// вместе с переводами сразу проверяется по списку запрещенных слов, совпадения видны модератору.
+func (api *api) SubmitProductHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	flags, err := api.findProductBannedWords(product.ID)
+	if err != nil {
+		http.Error(w, "Error checking product text", http.StatusInternalServerError)
+		return
+	}
+
+	err = api.db.SubmitProduct(product.ID, flags)
+	if errors.Is(err, repository.ErrInvalidProductStatus) {
+		http.Error(w, "Only draft or rejected products can be submitted for review", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error submitting product", http.StatusInternalServerError)
+		return
+	}
+
+	submitted, err := api.db.GetProductByID(product.ID)
+	if err != nil {
+		http.Error(w, "Error getting product", http.StatusInternalServerError)
+		return
+	}
+
+	api.publishProductEvent("product_submitted", submitted, nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(submitted)
+}
+
+// GetModerationQueueHandler возвращает товары на модерации (или в статусе из параметра status).
+func (api *api) GetModerationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := api.adminFromRequest(w, r); !ok {
+		return
+	}
+
+	status := models.ProductStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "":
+		status = models.ProductStatusPendingReview
+	case models.ProductStatusDraft, models.ProductStatusPendingReview, models.ProductStatusPublished, models.ProductStatusRejected:
+	default:
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	products, err := api.db.GetModerationQueue(status)
+	if err != nil {
+		http.Error(w, "Error getting moderation queue", http.StatusInternalServerError)
+		return
+	}
+	if products == nil {
+		products = []models.Product{}
+	}
+
+	if err := api.attachImages(products); err != nil {
+		http.Error(w, "Error getting product images", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+func (api *api) ModerateProductHandler(w http.ResponseWriter, r *http.Request) {
+	admin, ok := api.adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	var request models.ModerateProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Reason = strings.TrimSpace(request.Reason)
+
+	var status models.ProductStatus
+	var eventType string
+	switch request.Decision {
+	case models.DecisionApprove:
+		status, eventType = models.ProductStatusPublished, "product_approved"
+	case models.DecisionReject:
+		if request.Reason == "" {
+			http.Error(w, "Reason is required to reject a product", http.StatusBadRequest)
+			return
+		}
+		status, eventType = models.ProductStatusRejected, "product_rejected"
+	default:
+		http.Error(w, "Decision must be approve or reject", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := api.db.GetProductByID(productID); err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	err = api.db.ModerateProduct(productID, admin.ID, status, request.Reason)
+	if errors.Is(err, repository.ErrInvalidProductStatus) {
+		http.Error(w, "Product is not pending review", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error moderating product", http.StatusInternalServerError)
+		return
+	}
+
+	product, err := api.db.GetProductByID(productID)
+	if err != nil {
+		http.Error(w, "Error getting product", http.StatusInternalServerError)
+		return
+	}
+
+	api.publishModerationEvent(eventType, product, request.Reason)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+func (api *api) GetBannedWordsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := api.adminFromRequest(w, r); !ok {
+		return
+	}
+
+	words, err := api.db.GetBannedWords()
+	if err != nil {
+		http.Error(w, "Error getting banned words", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(words)
+}
+
+func (api *api) CreateBannedWordHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := api.adminFromRequest(w, r); !ok {
+		return
+	}
+
+	var request models.BannedWordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	word := models.NormalizeText(request.Word)
+	if word == "" {
+		http.Error(w, "Word is required", http.StatusBadRequest)
+		return
+	}
+
+	bannedWord, err := api.db.CreateBannedWord(word)
+	if errors.Is(err, repository.ErrBannedWordExists) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating banned word", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(bannedWord)
+}
+
+func (api *api) DeleteBannedWordHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := api.adminFromRequest(w, r); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid banned word id", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.DeleteBannedWord(id)
+	if errors.Is(err, repository.ErrBannedWordNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error deleting banned word", http.StatusInternalServerError)
+		return
+	}
+}
@@ -1,12 +1,16 @@
 package api
 
 import (
+	"Product_Service/internal/cache"
 	"Product_Service/internal/jwt"
 	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
 	"encoding/json"
+	"errors"
 	"github.com/gorilla/mux"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 func (api *api) CreateProductHandler(w http.ResponseWriter, r *http.Request) {
@@ -28,9 +32,51 @@ func (api *api) CreateProductHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	product.Price = product.Price.Normalize()
+	if err := product.Price.Validate(); err != nil {
+		http.Error(w, "Invalid price: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if product.Stock < 0 {
+		http.Error(w, "Stock cannot be negative", http.StatusBadRequest)
+		return
+	}
+
+	// Новый товар сохраняется черновиком, со status "pending_review" — сразу уходит на модерацию
+	switch product.Status {
+	case "", models.ProductStatusDraft:
+		product.Status = models.ProductStatusDraft
+	case models.ProductStatusPendingReview:
+		product.ModerationFlags, err = api.db.FindBannedWords(product.Name + " " + product.Description)
+		if err != nil {
+			http.Error(w, "Error checking product text", http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "New product status must be draft or pending_review", http.StatusBadRequest)
+		return
+	}
+
+	// default_locale — язык, на котором поставщик заполняет name и description
+	product.DefaultLocale = strings.ToLower(strings.TrimSpace(product.DefaultLocale))
+	if product.DefaultLocale == "" {
+		product.DefaultLocale = models.DefaultLocale
+	}
+	if !models.IsSupportedLocale(product.DefaultLocale) {
+		http.Error(w, "Invalid default locale: "+models.ErrUnsupportedLocale.Error(), http.StatusBadRequest)
+		return
+	}
+	product.Locale = product.DefaultLocale
+
+	product.SKU = strings.TrimSpace(product.SKU)
 	product.UserID = user.ID
 
 	productID, err := api.db.CreateProduct(product)
+	if errors.Is(err, repository.ErrDuplicateSKU) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Error creating product", http.StatusBadRequest)
 		return
@@ -38,10 +84,70 @@ func (api *api) CreateProductHandler(w http.ResponseWriter, r *http.Request) {
 
 	product.ID = productID
 
+	api.publishProductSnapshot("product_created", productID)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(product)
 }
 
+func (api *api) UpdateProductHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.UpdateProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		http.Error(w, "Product name is required", http.StatusBadRequest)
+		return
+	}
+
+	request.Price = request.Price.Normalize()
+	if err := request.Price.Validate(); err != nil {
+		http.Error(w, "Invalid price: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Измененный опубликованный товар снова уходит на модерацию и до решения не виден клиентам
+	if err := api.db.UpdateProduct(product.ID, request); err != nil {
+		http.Error(w, "Error updating product", http.StatusInternalServerError)
+		return
+	}
+
+	flags, err := api.findProductBannedWords(product.ID)
+	if err != nil {
+		http.Error(w, "Error checking product text", http.StatusInternalServerError)
+		return
+	}
+	if err := api.db.SetModerationFlags(product.ID, flags); err != nil {
+		http.Error(w, "Error updating product", http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := api.db.GetProductByID(product.ID)
+	if err != nil {
+		http.Error(w, "Error getting product", http.StatusInternalServerError)
+		return
+	}
+
+	api.publishProductEvent("product_updated", updated, nil)
+	if updated.Price != product.Price {
+		oldPrice := product.Price
+		api.publishProductEvent("product_price_changed", updated, &oldPrice)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteProductHandler архивирует товар. С параметром hard=true товар удаляется навсегда,
+// если он не встречался ни в одном заказе.
 func (api *api) DeleteProductHandler(w http.ResponseWriter, r *http.Request) {
 	user, err := api.validateUserToken(r)
 	if err != nil {
@@ -66,11 +172,75 @@ func (api *api) DeleteProductHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid product id", http.StatusBadRequest)
 		return
 	}
+	product, err := api.db.GetProductByID(id)
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	if product.UserID != user.ID {
+		http.Error(w, "You can only delete your own products", http.StatusForbidden)
+		return
+	}
+
+	if r.URL.Query().Get("hard") != "true" {
+		if err := api.db.ArchiveProduct(id); err != nil {
+			http.Error(w, "Error archiving a product", http.StatusInternalServerError)
+			return
+		}
+		api.publishProductSnapshot("product_archived", id)
+		return
+	}
+
+	images, err := api.db.GetProductImages(id)
+	if err != nil {
+		http.Error(w, "Error deleting a product", http.StatusInternalServerError)
+		return
+	}
+
 	err = api.db.DeleteProductByID(id)
+	if errors.Is(err, repository.ErrProductHasOrders) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Error deleting a product", http.StatusInternalServerError)
 		return
 	}
+
+	for _, image := range images {
+		api.deleteImageFiles(image)
+	}
+
+	api.publishProductEvent("product_deleted", product, nil)
+}
+
+func (api *api) RestoreProductHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if product.ArchivedAt == nil {
+		http.Error(w, "Product is not archived", http.StatusConflict)
+		return
+	}
+
+	if err := api.db.RestoreProduct(product.ID); err != nil {
+		http.Error(w, "Error restoring product", http.StatusInternalServerError)
+		return
+	}
+
+	restored, err := api.db.GetProductByID(product.ID)
+	if err != nil {
+		http.Error(w, "Error getting product", http.StatusInternalServerError)
+		return
+	}
+
+	api.publishProductEvent("product_restored", restored, nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(restored)
 }
 
 func (api *api) GetAllProductsForClientHandler(w http.ResponseWriter, r *http.Request) {
@@ -85,14 +255,41 @@ func (api *api) GetAllProductsForClientHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	products, err := api.db.GetAllProductsForClient()
+	var filter models.ProductFilter
+	if categoryIDStr := r.URL.Query().Get("category_id"); categoryIDStr != "" {
+		filter.CategoryID, err = strconv.Atoi(categoryIDStr)
+		if err != nil {
+			http.Error(w, "Invalid category id", http.StatusBadRequest)
+			return
+		}
+	}
+
+	locale, ok := requestLocale(w, r)
+	if !ok {
+		return
+	}
+
+	cacheKey := cache.ListKey("client", strconv.Itoa(filter.CategoryID), locale)
+	if api.cachedJSON(w, r, cacheKey) {
+		return
+	}
+
+	products, err := api.db.GetAllProductsForClient(filter)
 	if err != nil {
 		http.Error(w, "Error getting products", http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(products)
+	if err := api.attachImages(products); err != nil {
+		http.Error(w, "Error getting product images", http.StatusInternalServerError)
+		return
+	}
+	if err := api.localize(products, locale); err != nil {
+		http.Error(w, "Error getting product translations", http.StatusInternalServerError)
+		return
+	}
+
+	api.cacheJSON(w, r, cacheKey, listCacheTTL, products)
 }
 
 func (api *api) GetAllProductsForSupplierHandler(w http.ResponseWriter, r *http.Request) {
@@ -107,16 +304,36 @@ func (api *api) GetAllProductsForSupplierHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	products, err := api.db.GetAllProductsForSupplier(user.ID)
+	// archived=true показывает архив поставщика вместо активного каталога
+	var products []models.Product
+	if r.URL.Query().Get("archived") == "true" {
+		products, err = api.db.GetArchivedProductsForSupplier(user.ID)
+	} else {
+		products, err = api.db.GetAllProductsForSupplier(user.ID)
+	}
 	if err != nil {
 		http.Error(w, "Error getting products", http.StatusBadRequest)
 		return
 	}
 
+	if err := api.attachImages(products); err != nil {
+		http.Error(w, "Error getting product images", http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(products)
 }
 
+// optionalUser пропускает запросы без заголовка Authorization от имени гостя — пользователя
+// без id и роли, которому видны только опубликованные товары. Неверный токен по-прежнему ошибка.
+func (api *api) optionalUser(r *http.Request) (*models.User, error) {
+	if r.Header.Get("Authorization") == "" {
+		return &models.User{}, nil
+	}
+	return api.validateUserToken(r)
+}
+
 func (api *api) validateUserToken(r *http.Request) (*models.User, error) {
 	authHeader := r.Header.Get("Authorization")
 	tokenString, err := jwt.ExtractTokenFromHeader(authHeader)
@@ -137,3 +354,55 @@ func (api *api) validateUserToken(r *http.Request) (*models.User, error) {
 
 	return user, nil
 }
+
+func (api *api) UpdateProductStockHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "supplier" {
+		http.Error(w, "Only suppliers can update stock", http.StatusForbidden)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	var request models.UpdateStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.Stock < 0 {
+		http.Error(w, "Stock cannot be negative", http.StatusBadRequest)
+		return
+	}
+
+	product, err := api.db.GetProductByID(productID)
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	if product.UserID != user.ID {
+		http.Error(w, "You can only update stock of your own products", http.StatusForbidden)
+		return
+	}
+
+	if err := api.db.UpdateProductStock(productID, request.Stock); err != nil {
+		http.Error(w, "Error updating stock", http.StatusInternalServerError)
+		return
+	}
+
+	product.Stock = request.Stock
+	api.publishProductEvent("product_updated", product, nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
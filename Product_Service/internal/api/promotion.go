@@ -0,0 +1,128 @@
+package api
+
+import (
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func (api *api) CreatePromotionHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		http.Error(w, "Invalid promotion: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	variants, err := api.db.GetProductVariants(product.ID)
+	if err != nil {
+		http.Error(w, "Error getting product variants", http.StatusInternalServerError)
+		return
+	}
+	prices := []models.Money{product.Price}
+	for _, variant := range variants {
+		if variant.Price != nil {
+			prices = append(prices, *variant.Price)
+		}
+	}
+	if err := request.ValidateFor(prices); err != nil {
+		http.Error(w, "Invalid promotion: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	promotion, err := api.db.CreatePromotion(product.ID, request)
+	if err != nil {
+		http.Error(w, "Error creating promotion", http.StatusInternalServerError)
+		return
+	}
+
+	api.publishProductSnapshot("product_updated", product.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// GetProductPromotionsHandler показывает поставщику действующие и запланированные акции его товара.
+func (api *api) GetProductPromotionsHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	promotions, err := api.db.GetProductPromotions(product.ID)
+	if err != nil {
+		http.Error(w, "Error getting promotions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotions)
+}
+
+func (api *api) DeletePromotionHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	promotionID, err := strconv.Atoi(mux.Vars(r)["promotion_id"])
+	if err != nil {
+		http.Error(w, "Invalid promotion id", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.DeletePromotion(product.ID, promotionID)
+	if errors.Is(err, repository.ErrPromotionNotFound) {
+		http.Error(w, "Promotion not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error deleting promotion", http.StatusInternalServerError)
+		return
+	}
+
+	api.publishProductSnapshot("product_updated", product.ID)
+}
+
+func (api *api) GetPriceHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	_, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := api.db.GetProductByID(productID); err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	history, err := api.db.GetPriceHistory(productID)
+	if err != nil {
+		http.Error(w, "Error getting price history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
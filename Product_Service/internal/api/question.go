@@ -0,0 +1,227 @@
+package api
+
+import (
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const maxQuestionLength = 2000
+
+func (api *api) CreateQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "client" {
+		http.Error(w, "Only clients can ask questions", http.StatusForbidden)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	product, err := api.db.GetProductByID(productID)
+	if err != nil || !product.IsVisible() {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	var request models.QuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Text = strings.TrimSpace(request.Text)
+	if request.Text == "" || len([]rune(request.Text)) > maxQuestionLength {
+		http.Error(w, "Question text is required and must not be too long", http.StatusBadRequest)
+		return
+	}
+
+	if !api.checkBannedWords(w, request.Text) {
+		return
+	}
+
+	question, err := api.db.CreateQuestion(productID, user.ID, request.Text)
+	if err != nil {
+		http.Error(w, "Error creating question", http.StatusInternalServerError)
+		return
+	}
+
+	api.publishQuestionEvent("question_asked", product, question)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(question)
+}
+
+// GetProductQuestionsHandler доступен и без входа: вопросы опубликованного товара видны всем.
+func (api *api) GetProductQuestionsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.optionalUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	product, err := api.db.GetProductByID(productID)
+	if err != nil || (!product.IsVisible() && product.UserID != user.ID && user.Role != "admin") {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	questions, err := api.db.GetProductQuestions(productID, limit, offset)
+	if err != nil {
+		http.Error(w, "Error getting questions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(questions)
+}
+
+// AnswerQuestionHandler сохраняет ответ поставщика. Клиент получает уведомление только о первом ответе,
+// исправление ответа уведомление не отправляет.
+func (api *api) AnswerQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	questionID, err := strconv.Atoi(mux.Vars(r)["question_id"])
+	if err != nil {
+		http.Error(w, "Invalid question id", http.StatusBadRequest)
+		return
+	}
+
+	var request models.AnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Text = strings.TrimSpace(request.Text)
+	if request.Text == "" || len([]rune(request.Text)) > maxQuestionLength {
+		http.Error(w, "Answer text is required and must not be too long", http.StatusBadRequest)
+		return
+	}
+
+	if !api.checkBannedWords(w, request.Text) {
+		return
+	}
+
+	firstAnswer, err := api.db.AnswerQuestion(product.ID, questionID, request.Text)
+	if errors.Is(err, repository.ErrQuestionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error answering question", http.StatusInternalServerError)
+		return
+	}
+
+	question, err := api.db.GetQuestionByID(product.ID, questionID)
+	if err != nil {
+		http.Error(w, "Error getting question", http.StatusInternalServerError)
+		return
+	}
+
+	if firstAnswer {
+		api.publishQuestionEvent("question_answered", product, question)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(question)
+}
+
+// GetSupplierQuestionsHandler возвращает поставщику вопросы без ответа по всем его товарам.
+func (api *api) GetSupplierQuestionsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "supplier" {
+		http.Error(w, "Only suppliers can get their questions", http.StatusForbidden)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	questions, err := api.db.GetUnansweredQuestionsForSupplier(user.ID, limit, offset)
+	if err != nil {
+		http.Error(w, "Error getting questions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(questions)
+}
+
+// checkBannedWords не пропускает вопросы и ответы с запрещенными словами: они публикуются сразу, без модерации.
+// При ошибке ответ уже записан в w.
+func (api *api) checkBannedWords(w http.ResponseWriter, text string) bool {
+	words, err := api.db.FindBannedWords(text)
+	if err != nil {
+		http.Error(w, "Error checking text", http.StatusInternalServerError)
+		return false
+	}
+	if len(words) > 0 {
+		http.Error(w, "Text contains banned words: "+strings.Join(words, ", "), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// publishQuestionEvent отправляет событие вопроса в product-events; Notification Service
+// пишет поставщику о новом вопросе и клиенту об ответе.
+func (api *api) publishQuestionEvent(eventType string, product models.Product, question models.Question) {
+	if api.producer == nil {
+		return
+	}
+
+	event := models.QuestionEvent{
+		EventType:   eventType,
+		QuestionID:  question.ID,
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		SupplierID:  product.UserID,
+		ClientID:    question.ClientID,
+		Question:    question.Text,
+		Answer:      question.Answer,
+		Timestamp:   time.Now(),
+	}
+	if err := api.producer.PublishKeyedMessage(productEventsTopic, strconv.Itoa(product.ID), event); err != nil {
+		log.Printf("Failed to publish %s event: %v", eventType, err)
+	}
+}
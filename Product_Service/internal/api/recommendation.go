@@ -0,0 +1,92 @@
+package api
+
+import (
+	"Product_Service/internal/models"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultRecommendations = 10
+	maxRecommendations     = 50
+)
+
+// GetRecommendationsHandler возвращает товары, которые покупают вместе с данным. Если истории
+// совместных покупок мало, список дополняется популярными товарами.
+func (api *api) GetRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	_, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultRecommendations
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > maxRecommendations {
+			http.Error(w, errInvalidLimit.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	locale, ok := requestLocale(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := api.db.GetProductByID(productID); err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	recommendations, err := api.db.GetBoughtTogether(productID, limit)
+	if err != nil {
+		http.Error(w, "Error getting recommendations", http.StatusInternalServerError)
+		return
+	}
+
+	if len(recommendations) < limit {
+		exclude := make([]int, 0, len(recommendations))
+		for _, recommendation := range recommendations {
+			exclude = append(exclude, recommendation.Product.ID)
+		}
+
+		popular, err := api.db.GetPopularProducts(productID, exclude, limit-len(recommendations))
+		if err != nil {
+			http.Error(w, "Error getting recommendations", http.StatusInternalServerError)
+			return
+		}
+		recommendations = append(recommendations, popular...)
+	}
+	if recommendations == nil {
+		recommendations = []models.Recommendation{}
+	}
+
+	products := make([]models.Product, len(recommendations))
+	for i, recommendation := range recommendations {
+		products[i] = recommendation.Product
+	}
+	if err := api.attachImages(products); err != nil {
+		http.Error(w, "Error getting product images", http.StatusInternalServerError)
+		return
+	}
+	if err := api.localize(products, locale); err != nil {
+		http.Error(w, "Error getting product translations", http.StatusInternalServerError)
+		return
+	}
+	for i := range recommendations {
+		recommendations[i].Product = products[i]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recommendations)
+}
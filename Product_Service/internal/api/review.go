@@ -0,0 +1,346 @@
+package api
+
+import (
+	"Product_Service/internal/cache"
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+const maxReviewLength = 5000
+
+func (api *api) CreateReviewHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "client" {
+		http.Error(w, "Only clients can review products", http.StatusForbidden)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	var request models.CreateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.Rating < 1 || request.Rating > 5 {
+		http.Error(w, "Rating must be between 1 and 5", http.StatusBadRequest)
+		return
+	}
+
+	request.Text = strings.TrimSpace(request.Text)
+	if len([]rune(request.Text)) > maxReviewLength {
+		http.Error(w, "Review text is too long", http.StatusBadRequest)
+		return
+	}
+
+	review, err := api.db.CreateReview(productID, user.ID, request)
+	switch {
+	case errors.Is(err, repository.ErrNotVerifiedBuyer):
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case errors.Is(err, repository.ErrReviewExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Error creating review", http.StatusInternalServerError)
+		return
+	}
+
+	// Отзыв меняет рейтинг товара в каталоге
+	api.invalidateProduct(productID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(api.reviewPhotoURLs([]models.Review{review})[0])
+}
+
+func (api *api) GetProductReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	_, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reviews, err := api.db.GetPublishedReviews(productID, limit, offset)
+	if err != nil {
+		http.Error(w, "Error getting reviews", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.reviewPhotoURLs(reviews))
+}
+
+func (api *api) UploadReviewPhotoHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	review, ok := api.reviewFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if review.ClientID != user.ID {
+		http.Error(w, "You can only add photos to your own review", http.StatusForbidden)
+		return
+	}
+
+	upload, ok := readImageUpload(w, r, "photo")
+	if !ok {
+		return
+	}
+
+	name, err := randomName()
+	if err != nil {
+		http.Error(w, "Error saving photo", http.StatusInternalServerError)
+		return
+	}
+
+	key := fmt.Sprintf("reviews/%d/%s.%s", review.ID, name, upload.extension)
+	if err := api.storage.Put(r.Context(), key, bytes.NewReader(upload.data), upload.contentType); err != nil {
+		http.Error(w, "Error saving photo", http.StatusInternalServerError)
+		return
+	}
+
+	if err := api.db.AddReviewPhoto(review.ID, key); err != nil {
+		api.storage.Delete(r.Context(), key)
+		if errors.Is(err, repository.ErrTooManyReviewPhotos) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error saving photo", http.StatusInternalServerError)
+		return
+	}
+
+	review.Photos = append(review.Photos, key)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.reviewPhotoURLs([]models.Review{review})[0])
+}
+
+func (api *api) ReplyToReviewHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	reviewID, err := strconv.Atoi(mux.Vars(r)["review_id"])
+	if err != nil {
+		http.Error(w, "Invalid review id", http.StatusBadRequest)
+		return
+	}
+
+	var request models.ReviewReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Text = strings.TrimSpace(request.Text)
+	if request.Text == "" || len([]rune(request.Text)) > maxReviewLength {
+		http.Error(w, "Reply text is required and must not be too long", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.ReplyToReview(product.ID, reviewID, request.Text)
+	if errors.Is(err, repository.ErrReviewNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error replying to review", http.StatusInternalServerError)
+		return
+	}
+
+	review, err := api.db.GetReviewByID(product.ID, reviewID)
+	if err != nil {
+		http.Error(w, "Error getting review", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.reviewPhotoURLs([]models.Review{review})[0])
+}
+
+func (api *api) FlagReviewHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	reviewID, err := strconv.Atoi(mux.Vars(r)["review_id"])
+	if err != nil {
+		http.Error(w, "Invalid review id", http.StatusBadRequest)
+		return
+	}
+
+	var request models.FlagReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.FlagReview(productID, reviewID, user.ID, strings.TrimSpace(request.Reason))
+	switch {
+	case errors.Is(err, repository.ErrReviewNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, repository.ErrAlreadyFlagged):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Error flagging review", http.StatusInternalServerError)
+		return
+	}
+
+	api.invalidateProduct(productID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "flagged"})
+}
+
+func (api *api) GetFlaggedReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "admin" {
+		http.Error(w, "Only admins can moderate reviews", http.StatusForbidden)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reviews, err := api.db.GetFlaggedReviews(limit, offset)
+	if err != nil {
+		http.Error(w, "Error getting reviews", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.reviewPhotoURLs(reviews))
+}
+
+func (api *api) ModerateReviewHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if user.Role != "admin" {
+		http.Error(w, "Only admins can moderate reviews", http.StatusForbidden)
+		return
+	}
+
+	reviewID, err := strconv.Atoi(mux.Vars(r)["review_id"])
+	if err != nil {
+		http.Error(w, "Invalid review id", http.StatusBadRequest)
+		return
+	}
+
+	var request models.ModerateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.Status != models.ReviewStatusPublished && request.Status != models.ReviewStatusHidden {
+		http.Error(w, "Status must be published or hidden", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.ModerateReview(reviewID, request.Status)
+	if errors.Is(err, repository.ErrReviewNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error moderating review", http.StatusInternalServerError)
+		return
+	}
+
+	// Товар отзыва здесь неизвестен, а модерация отзывов редкая — сбрасываем кэш каталога целиком
+	cache.InvalidateAll(api.cache)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": string(request.Status)})
+}
+
+func (api *api) reviewFromRequest(w http.ResponseWriter, r *http.Request) (models.Review, bool) {
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return models.Review{}, false
+	}
+
+	reviewID, err := strconv.Atoi(mux.Vars(r)["review_id"])
+	if err != nil {
+		http.Error(w, "Invalid review id", http.StatusBadRequest)
+		return models.Review{}, false
+	}
+
+	review, err := api.db.GetReviewByID(productID, reviewID)
+	if err != nil {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return models.Review{}, false
+	}
+	return review, true
+}
+
+// reviewPhotoURLs заменяет ключи хранилища в фотографиях отзывов на публичные URL.
+func (api *api) reviewPhotoURLs(reviews []models.Review) []models.Review {
+	for i := range reviews {
+		for j, key := range reviews[i].Photos {
+			reviews[i].Photos[j] = api.storage.URL(key)
+		}
+	}
+	return reviews
+}
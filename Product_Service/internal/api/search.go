@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+var (
+	errInvalidLimit  = errors.New("invalid limit")
+	errInvalidOffset = errors.New("invalid offset")
+)
+
+func (api *api) SearchProductsHandler(w http.ResponseWriter, r *http.Request) {
+	_, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		http.Error(w, "Search query is required", http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	locale, ok := requestLocale(w, r)
+	if !ok {
+		return
+	}
+
+	results, err := api.db.SearchProducts(query, locale, limit, offset)
+	if err != nil {
+		http.Error(w, "Error searching products", http.StatusInternalServerError)
+		return
+	}
+
+	ids := make([]int, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	images, err := api.db.GetImagesForProducts(ids)
+	if err != nil {
+		http.Error(w, "Error getting product images", http.StatusInternalServerError)
+		return
+	}
+	for i := range results {
+		results[i].Images = api.imageURLs(images[results[i].ID])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+func parsePagination(r *http.Request) (int, int, error) {
+	limit := defaultPageLimit
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value <= 0 {
+			return 0, 0, errInvalidLimit
+		}
+		limit = min(value, maxPageLimit)
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		value, err := strconv.Atoi(offsetStr)
+		if err != nil || value < 0 {
+			return 0, 0, errInvalidOffset
+		}
+		offset = value
+	}
+
+	return limit, offset, nil
+}
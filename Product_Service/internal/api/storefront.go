@@ -0,0 +1,80 @@
+package api
+
+import (
+	"Product_Service/internal/models"
+	"Product_Service/internal/users"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GetSupplierStorefrontHandler — публичная витрина поставщика: профиль из User Service,
+// статистика и страница активных товаров. Токен не требуется.
+func (api *api) GetSupplierStorefrontHandler(w http.ResponseWriter, r *http.Request) {
+	supplierID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid supplier id", http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := api.users.GetUser(supplierID)
+	if errors.Is(err, users.ErrUserNotFound) || (err == nil && user.Role != "supplier") {
+		http.Error(w, "Supplier not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get supplier %d: %v", supplierID, err)
+		http.Error(w, "User service unavailable", http.StatusBadGateway)
+		return
+	}
+
+	locale, ok := requestLocale(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := api.db.GetSupplierStats(supplierID)
+	if err != nil {
+		http.Error(w, "Error getting supplier stats", http.StatusInternalServerError)
+		return
+	}
+
+	products, err := api.db.GetSupplierStorefrontProducts(supplierID, limit, offset)
+	if err != nil {
+		http.Error(w, "Error getting products", http.StatusInternalServerError)
+		return
+	}
+	if products == nil {
+		products = []models.Product{}
+	}
+
+	if err := api.attachImages(products); err != nil {
+		http.Error(w, "Error getting product images", http.StatusInternalServerError)
+		return
+	}
+	if err := api.localize(products, locale); err != nil {
+		http.Error(w, "Error getting product translations", http.StatusInternalServerError)
+		return
+	}
+
+	storefront := models.Storefront{
+		Supplier: models.SupplierInfo{ID: user.ID, Username: user.Username},
+		Stats:    stats,
+		Products: products,
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(storefront)
+}
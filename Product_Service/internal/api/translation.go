@@ -0,0 +1,228 @@
+package api
+
+import (
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+func (api *api) GetProductTranslationsHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	translations, err := api.db.GetProductTranslations(product.ID)
+	if err != nil {
+		http.Error(w, "Error getting translations", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translations)
+}
+
+// SetProductTranslationHandler создает или заменяет перевод товара на язык {locale}.
+// Текст на языке по умолчанию меняется обычным обновлением товара.
+func (api *api) SetProductTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	locale, ok := translationLocale(w, r, product)
+	if !ok {
+		return
+	}
+
+	var request models.TranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		http.Error(w, "Product name is required", http.StatusBadRequest)
+		return
+	}
+
+	translation, err := api.db.SetProductTranslation(product.ID, locale, request)
+	if err != nil {
+		http.Error(w, "Error saving translation", http.StatusInternalServerError)
+		return
+	}
+
+	api.translationChanged(product.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translation)
+}
+
+func (api *api) DeleteProductTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	locale, ok := translationLocale(w, r, product)
+	if !ok {
+		return
+	}
+
+	err := api.db.DeleteProductTranslation(product.ID, locale)
+	if errors.Is(err, repository.ErrTranslationNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error deleting translation", http.StatusInternalServerError)
+		return
+	}
+
+	api.translationChanged(product.ID)
+}
+
+// translationLocale читает {locale} из пути и проверяет, что для него можно хранить перевод.
+// При ошибке ответ уже записан в w.
+func translationLocale(w http.ResponseWriter, r *http.Request, product models.Product) (string, bool) {
+	locale := strings.ToLower(mux.Vars(r)["locale"])
+	if !models.IsSupportedLocale(locale) {
+		http.Error(w, models.ErrUnsupportedLocale.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	if locale == product.DefaultLocale {
+		http.Error(w, "Text in the default language is changed by updating the product", http.StatusBadRequest)
+		return "", false
+	}
+	return locale, true
+}
+
+// translationChanged перепроверяет текст товара по запрещенным словам и публикует обновление товара.
+// Перевод к этому моменту уже сохранен, поэтому ошибка проверки только записывается в лог:
+// опубликованный товар с новым переводом и так ждет решения модератора.
+func (api *api) translationChanged(productID int) {
+	flags, err := api.findProductBannedWords(productID)
+	if err == nil {
+		err = api.db.SetModerationFlags(productID, flags)
+	}
+	if err != nil {
+		log.Printf("Failed to update moderation flags for product %d: %v", productID, err)
+	}
+
+	api.publishProductSnapshot("product_updated", productID)
+}
+
+// findProductBannedWords проверяет по списку запрещенных слов весь текст товара вместе с переводами.
+func (api *api) findProductBannedWords(productID int) ([]string, error) {
+	text, err := api.db.GetProductModerationText(productID)
+	if err != nil {
+		return nil, err
+	}
+	return api.db.FindBannedWords(text)
+}
+
+// requestLocale выбирает язык ответа: параметр lang важнее заголовка Accept-Language.
+// Пустая строка означает, что клиент не выбрал поддерживаемый язык и товары отдаются на языке поставщика.
+// При ошибке ответ уже записан в w.
+func requestLocale(w http.ResponseWriter, r *http.Request) (string, bool) {
+	w.Header().Add("Vary", "Accept-Language")
+
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		locale := primaryLanguage(lang)
+		if !models.IsSupportedLocale(locale) {
+			http.Error(w, models.ErrUnsupportedLocale.Error(), http.StatusBadRequest)
+			return "", false
+		}
+		return locale, true
+	}
+
+	return negotiateLocale(r.Header.Get("Accept-Language")), true
+}
+
+// negotiateLocale выбирает из Accept-Language поддерживаемый язык с наибольшим весом q.
+func negotiateLocale(header string) string {
+	type languageRange struct {
+		locale string
+		q      float64
+	}
+
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		locale := primaryLanguage(fields[0])
+		if !models.IsSupportedLocale(locale) {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || name != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+		if q > 0 {
+			ranges = append(ranges, languageRange{locale: locale, q: q})
+		}
+	}
+
+	// При равном весе выигрывает язык, указанный раньше
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	if len(ranges) == 0 {
+		return ""
+	}
+	return ranges[0].locale
+}
+
+// primaryLanguage оставляет от тега языка основной subtag: "en-US" -> "en".
+func primaryLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// localize подставляет в товары переводы на locale одним запросом.
+// Товары без перевода остаются на языке поставщика.
+func (api *api) localize(products []models.Product, locale string) error {
+	if locale == "" {
+		return nil
+	}
+
+	ids := make([]int, 0, len(products))
+	for _, product := range products {
+		if product.DefaultLocale != locale {
+			ids = append(ids, product.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	translations, err := api.db.GetTranslationsForProducts(ids, locale)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		if translation, ok := translations[products[i].ID]; ok {
+			products[i].Localize(&translation)
+		}
+	}
+	return nil
+}
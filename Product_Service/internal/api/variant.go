@@ -0,0 +1,327 @@
+package api
+
+import (
+	"Product_Service/internal/cache"
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+func (api *api) GetProductHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.optionalUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	locale, ok := requestLocale(w, r)
+	if !ok {
+		return
+	}
+
+	// В кэше лежат только опубликованные товары, их карточка одинакова для всех пользователей
+	cacheKey := cache.ProductKey(productID, locale)
+	if api.cachedJSON(w, r, cacheKey) {
+		return
+	}
+
+	// Скрытый товар (в архиве или не опубликованный) видят только его поставщик и администратор
+	product, err := api.db.GetProductByID(productID)
+	if err != nil || (!product.IsVisible() && product.UserID != user.ID && user.Role != "admin") {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	products := []models.Product{product}
+	if err := api.attachImages(products); err != nil {
+		http.Error(w, "Error getting product images", http.StatusInternalServerError)
+		return
+	}
+	if err := api.localize(products, locale); err != nil {
+		http.Error(w, "Error getting product translations", http.StatusInternalServerError)
+		return
+	}
+	product = products[0]
+
+	product.Options, err = api.db.GetProductOptions(productID)
+	if err != nil {
+		http.Error(w, "Error getting product options", http.StatusInternalServerError)
+		return
+	}
+
+	product.Variants, err = api.db.GetProductVariants(productID)
+	if err != nil {
+		http.Error(w, "Error getting product variants", http.StatusInternalServerError)
+		return
+	}
+
+	if !product.IsVisible() {
+		cacheKey = ""
+	}
+	api.cacheJSON(w, r, cacheKey, productCacheTTL, product)
+}
+
+func (api *api) SetProductOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.SetProductOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	seen := map[string]bool{}
+	for i, option := range request.Options {
+		option.Name = strings.TrimSpace(option.Name)
+		if option.Name == "" || len(option.Values) == 0 || seen[option.Name] {
+			http.Error(w, "Each option needs a unique name and at least one value", http.StatusBadRequest)
+			return
+		}
+		seen[option.Name] = true
+		request.Options[i] = option
+	}
+
+	// Существующие варианты должны остаться допустимыми при новых типах опций
+	variants, err := api.db.GetProductVariants(product.ID)
+	if err != nil {
+		http.Error(w, "Error getting product variants", http.StatusInternalServerError)
+		return
+	}
+	for _, variant := range variants {
+		if err := models.ValidateVariantOptions(request.Options, variant.Options); err != nil {
+			http.Error(w, "Variant "+variant.SKU+" does not fit new options: "+err.Error(), http.StatusConflict)
+			return
+		}
+	}
+
+	if err := api.db.SetProductOptions(product.ID, request.Options); err != nil {
+		http.Error(w, "Error setting product options", http.StatusInternalServerError)
+		return
+	}
+	api.invalidateProduct(product.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(request.Options)
+}
+
+// GetProductVariantsHandler возвращает варианты товара. Параметры запроса с именами опций
+// (например, ?size=M&color=red) сужают выборку до вариантов с этими значениями.
+// Варианты скрытого товара, как и сам товар, видят только его поставщик и администратор.
+func (api *api) GetProductVariantsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	productID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	product, err := api.db.GetProductByID(productID)
+	if err != nil || (!product.IsVisible() && product.UserID != user.ID && user.Role != "admin") {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	options, err := api.db.GetProductOptions(productID)
+	if err != nil {
+		http.Error(w, "Error getting product options", http.StatusInternalServerError)
+		return
+	}
+
+	selected := map[string]string{}
+	for _, option := range options {
+		if value := r.URL.Query().Get(option.Name); value != "" {
+			selected[option.Name] = value
+		}
+	}
+
+	variants, err := api.db.GetProductVariants(productID)
+	if err != nil {
+		http.Error(w, "Error getting product variants", http.StatusInternalServerError)
+		return
+	}
+
+	matching := []models.ProductVariant{}
+	for _, variant := range variants {
+		if variantMatches(variant, selected) {
+			matching = append(matching, variant)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matching)
+}
+
+func (api *api) CreateVariantHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	request, ok := api.decodeVariantRequest(w, r, product)
+	if !ok {
+		return
+	}
+
+	variantID, err := api.db.CreateVariant(product.ID, request)
+	if errors.Is(err, repository.ErrDuplicateVariant) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating variant", http.StatusInternalServerError)
+		return
+	}
+	api.invalidateProduct(product.ID)
+
+	variant, err := api.db.GetVariantByID(product.ID, variantID)
+	if err != nil {
+		http.Error(w, "Error getting variant", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(variant)
+}
+
+func (api *api) UpdateVariantHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	variantID, err := strconv.Atoi(mux.Vars(r)["variant_id"])
+	if err != nil {
+		http.Error(w, "Invalid variant id", http.StatusBadRequest)
+		return
+	}
+
+	request, ok := api.decodeVariantRequest(w, r, product)
+	if !ok {
+		return
+	}
+
+	err = api.db.UpdateVariant(product.ID, variantID, request)
+	switch {
+	case errors.Is(err, repository.ErrVariantNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, repository.ErrDuplicateVariant):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Error updating variant", http.StatusInternalServerError)
+		return
+	}
+	api.invalidateProduct(product.ID)
+
+	variant, err := api.db.GetVariantByID(product.ID, variantID)
+	if err != nil {
+		http.Error(w, "Error getting variant", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(variant)
+}
+
+func (api *api) DeleteVariantHandler(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.ownProductFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	variantID, err := strconv.Atoi(mux.Vars(r)["variant_id"])
+	if err != nil {
+		http.Error(w, "Invalid variant id", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.DeleteVariant(product.ID, variantID)
+	if errors.Is(err, repository.ErrVariantNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error deleting variant", http.StatusInternalServerError)
+		return
+	}
+	api.invalidateProduct(product.ID)
+}
+
+func (api *api) decodeVariantRequest(w http.ResponseWriter, r *http.Request, product models.Product) (models.VariantRequest, bool) {
+	var request models.VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return request, false
+	}
+
+	request.SKU = strings.TrimSpace(request.SKU)
+	if request.SKU == "" {
+		http.Error(w, "SKU is required", http.StatusBadRequest)
+		return request, false
+	}
+
+	if request.Stock < 0 {
+		http.Error(w, "Stock cannot be negative", http.StatusBadRequest)
+		return request, false
+	}
+
+	if request.Price != nil {
+		price := request.Price.Normalize()
+		if price.Currency != product.Price.Currency {
+			http.Error(w, "Variant price must be in product currency "+product.Price.Currency, http.StatusBadRequest)
+			return request, false
+		}
+		if err := price.Validate(); err != nil {
+			http.Error(w, "Invalid price: "+err.Error(), http.StatusBadRequest)
+			return request, false
+		}
+		request.Price = &price
+	}
+
+	options, err := api.db.GetProductOptions(product.ID)
+	if err != nil {
+		http.Error(w, "Error getting product options", http.StatusInternalServerError)
+		return request, false
+	}
+
+	if request.Options == nil {
+		request.Options = map[string]string{}
+	}
+	if err := models.ValidateVariantOptions(options, request.Options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return request, false
+	}
+
+	return request, true
+}
+
+func variantMatches(variant models.ProductVariant, selected map[string]string) bool {
+	for name, value := range selected {
+		if variant.Options[name] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"Product_Service/internal/models"
+	"Product_Service/internal/repository"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// clientFromRequest проверяет токен и то, что запрос сделал клиент.
+func (api *api) clientFromRequest(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	user, err := api.validateUserToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+
+	if user.Role != "client" {
+		http.Error(w, "Only clients can manage wishlists", http.StatusForbidden)
+		return nil, false
+	}
+	return user, true
+}
+
+func (api *api) GetWishlistsHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := api.clientFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	locale, ok := requestLocale(w, r)
+	if !ok {
+		return
+	}
+
+	wishlists, err := api.db.GetClientWishlists(user.ID)
+	if err != nil {
+		http.Error(w, "Error getting wishlists", http.StatusInternalServerError)
+		return
+	}
+
+	for _, wishlist := range wishlists {
+		if err := api.attachImages(wishlist.Products); err != nil {
+			http.Error(w, "Error getting product images", http.StatusInternalServerError)
+			return
+		}
+		if err := api.localize(wishlist.Products, locale); err != nil {
+			http.Error(w, "Error getting product translations", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wishlists)
+}
+
+func (api *api) CreateWishlistHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := api.clientFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.WishlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		http.Error(w, "Wishlist name is required", http.StatusBadRequest)
+		return
+	}
+
+	wishlist, err := api.db.CreateWishlist(user.ID, request.Name)
+	if errors.Is(err, repository.ErrWishlistExists) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating wishlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+func (api *api) DeleteWishlistHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := api.clientFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	wishlistID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid wishlist id", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.DeleteWishlist(user.ID, wishlistID)
+	if errors.Is(err, repository.ErrWishlistNotFound) {
+		http.Error(w, "Wishlist not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error deleting wishlist", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (api *api) AddWishlistItemHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := api.clientFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	wishlistID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid wishlist id", http.StatusBadRequest)
+		return
+	}
+
+	var request models.WishlistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	product, err := api.db.GetProductByID(request.ProductID)
+	if err != nil || !product.IsVisible() {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	err = api.db.AddWishlistItem(user.ID, wishlistID, product)
+	if errors.Is(err, repository.ErrWishlistNotFound) {
+		http.Error(w, "Wishlist not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error adding product to wishlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (api *api) RemoveWishlistItemHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := api.clientFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	wishlistID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid wishlist id", http.StatusBadRequest)
+		return
+	}
+
+	productID, err := strconv.Atoi(vars["product_id"])
+	if err != nil {
+		http.Error(w, "Invalid product id", http.StatusBadRequest)
+		return
+	}
+
+	err = api.db.RemoveWishlistItem(user.ID, wishlistID, productID)
+	if errors.Is(err, repository.ErrWishlistItemNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error removing product from wishlist", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"strconv"
+	"time"
+)
+
+// Cache хранит готовые ответы каталога по ключу с ограниченным временем жизни.
+// Реализация в памяти процесса подходит для одного экземпляра сервиса; общий кэш
+// (например, Redis) можно подключить, реализовав этот интерфейс.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	DeletePrefix(prefix string)
+}
+
+// Ключи строятся по префиксам, чтобы изменение товара сбрасывало его карточку и все списки одним вызовом.
+const (
+	productPrefix = "product:"
+	listPrefix    = "list:"
+)
+
+// ProductKey — ключ карточки товара на языке locale.
+func ProductKey(productID int, locale string) string {
+	return productPrefix + strconv.Itoa(productID) + ":" + locale
+}
+
+// ListKey — ключ списка товаров; parts описывают фильтры и язык запроса.
+func ListKey(name string, parts ...string) string {
+	key := listPrefix + name
+	for _, part := range parts {
+		key += ":" + part
+	}
+	return key
+}
+
+// InvalidateProduct сбрасывает карточку товара и все списки, в которые он мог попасть.
+func InvalidateProduct(c Cache, productID int) {
+	if c == nil {
+		return
+	}
+	c.DeletePrefix(productPrefix + strconv.Itoa(productID) + ":")
+	c.DeletePrefix(listPrefix)
+}
+
+// InvalidateAll сбрасывает весь кэш каталога, когда нельзя понять, какие товары изменились.
+func InvalidateAll(c Cache) {
+	if c == nil {
+		return
+	}
+	c.DeletePrefix("")
+}
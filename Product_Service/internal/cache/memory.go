@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache — кэш в памяти процесса. При переполнении сначала удаляются просроченные
+// записи, а если их нет — кэш очищается целиком: ответы каталога дешево пересобрать.
+type MemoryCache struct {
+	mu         sync.RWMutex
+	entries    map[string]memoryEntry
+	maxEntries int
+}
+
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryEntry), maxEntries: maxEntries}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		c.evictExpired()
+		if len(c.entries) >= c.maxEntries {
+			c.entries = make(map[string]memoryEntry)
+		}
+	}
+	c.entries[key] = memoryEntry{value: value, expiresAt: time.Now().Add(ttl)}
+}
+
+func (c *MemoryCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *MemoryCache) evictExpired() {
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/IBM/sarama"
+)
+
+type Producer struct {
+	producer sarama.SyncProducer
+}
+
+func NewProducer(brokers []string) (*Producer, error) {
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 5
+	config.Producer.Return.Successes = true
+
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Producer{producer: producer}, nil
+}
+
+func (p *Producer) PublishMessage(topic string, message interface{}) error {
+	return p.PublishKeyedMessage(topic, "", message)
+}
+
+// PublishKeyedMessage отправляет сообщение с ключом: сообщения с одинаковым ключом
+// попадают в одну партицию и читаются в порядке отправки.
+func (p *Producer) PublishKeyedMessage(topic string, key string, message interface{}) error {
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.StringEncoder(messageBytes),
+	}
+	if key != "" {
+		msg.Key = sarama.StringEncoder(key)
+	}
+
+	partition, offset, err := p.producer.SendMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Message sent to topic %s, partition %d, offset %d", topic, partition, offset)
+	return nil
+}
+
+func (p *Producer) Close() error {
+	return p.producer.Close()
+}
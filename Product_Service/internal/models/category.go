@@ -0,0 +1,19 @@
+package models
+
+type Category struct {
+	ID                int         `json:"id"`
+	Name              string      `json:"name"`
+	ParentID          *int        `json:"parent_id"`
+	ProductCount      int         `json:"product_count"`
+	TotalProductCount int         `json:"total_product_count"`
+	Children          []*Category `json:"children,omitempty"`
+}
+
+type CategoryRequest struct {
+	Name     string `json:"name"`
+	ParentID *int   `json:"parent_id"`
+}
+
+type ProductCategoriesRequest struct {
+	CategoryIDs []int `json:"category_ids"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+type OrderEvent struct {
+	EventType   string      `json:"event_type"`
+	OrderID     int         `json:"order_id"`
+	ProductName string      `json:"product_name"`
+	ProductID   int         `json:"product_id"`
+	VariantID   int         `json:"variant_id,omitempty"`
+	SupplierID  int         `json:"supplier_id"`
+	ClientID    int         `json:"client_id"`
+	Quantity    int         `json:"quantity,omitempty"`
+	Items       []OrderItem `json:"items,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	Reason      string      `json:"reason,omitempty"`
+	Timestamp   time.Time   `json:"timestamp"`
+}
+
+type OrderItem struct {
+	ProductID   int    `json:"product_id"`
+	VariantID   int    `json:"variant_id,omitempty"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+}
+
+// OrderItems возвращает позиции заказа. В событиях старого формата позиций нет,
+// тогда заказ состоит из одного товара из полей события.
+func (e OrderEvent) OrderItems() []OrderItem {
+	if len(e.Items) > 0 {
+		return e.Items
+	}
+	return []OrderItem{{ProductID: e.ProductID, VariantID: e.VariantID, ProductName: e.ProductName, Quantity: e.Quantity}}
+}
+
+type PaymentEvent struct {
+	EventType string    `json:"event_type"`
+	PaymentID string    `json:"payment_id"`
+	OrderID   int       `json:"order_id"`
+	ClientID  int       `json:"client_id"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type ProductEvent struct {
+	EventType  string    `json:"event_type"`
+	ProductID  int       `json:"product_id"`
+	SupplierID int       `json:"supplier_id"`
+	Product    Product   `json:"product"`
+	OldPrice   *Money    `json:"old_price,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// WishlistEvent сообщает клиенту о снижении цены (wishlist_price_drop) или поступлении
+// (wishlist_back_in_stock) товара из его списков желаний.
+type WishlistEvent struct {
+	EventType   string    `json:"event_type"`
+	ClientID    int       `json:"client_id"`
+	ProductID   int       `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	OldPrice    *Money    `json:"old_price,omitempty"`
+	Price       Money     `json:"price"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type StockEvent struct {
+	EventType string      `json:"event_type"`
+	OrderID   int         `json:"order_id"`
+	ClientID  int         `json:"client_id"`
+	Items     []StockItem `json:"items"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// StockItem — позиция заказа и текущий остаток товара или варианта после резервирования.
+type StockItem struct {
+	ProductID int `json:"product_id"`
+	VariantID int `json:"variant_id,omitempty"`
+	Quantity  int `json:"quantity"`
+	Available int `json:"available"`
+}
+
+// QuestionEvent сообщает поставщику о новом вопросе (question_asked), а клиенту — об ответе (question_answered).
+type QuestionEvent struct {
+	EventType   string    `json:"event_type"`
+	QuestionID  int       `json:"question_id"`
+	ProductID   int       `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	SupplierID  int       `json:"supplier_id"`
+	ClientID    int       `json:"client_id"`
+	Question    string    `json:"question"`
+	Answer      string    `json:"answer,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type ProductImage struct {
+	ID           int       `json:"id"`
+	ProductID    int       `json:"product_id"`
+	StorageKey   string    `json:"-"`
+	ThumbnailKey string    `json:"-"`
+	ContentType  string    `json:"content_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Position     int       `json:"position"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ReorderImagesRequest struct {
+	ImageIDs []int `json:"image_ids"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+type ImportJobStatus string
+
+const (
+	ImportJobPending    ImportJobStatus = "pending"
+	ImportJobProcessing ImportJobStatus = "processing"
+	ImportJobCompleted  ImportJobStatus = "completed"
+	ImportJobFailed     ImportJobStatus = "failed"
+)
+
+type ImportJob struct {
+	ID            int              `json:"id"`
+	SupplierID    int              `json:"supplier_id"`
+	Status        ImportJobStatus  `json:"status"`
+	Filename      string           `json:"filename"`
+	Format        string           `json:"format"`
+	TotalRows     int              `json:"total_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	CreatedCount  int              `json:"created_count"`
+	UpdatedCount  int              `json:"updated_count"`
+	ErrorCount    int              `json:"error_count"`
+	Errors        []ImportRowError `json:"errors"`
+	CreatedAt     time.Time        `json:"created_at"`
+	FinishedAt    *time.Time       `json:"finished_at,omitempty"`
+}
+
+// ImportRowError описывает строку файла, которую не удалось импортировать. Row — номер строки в файле, считая заголовок.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportProductRow — разобранная строка файла импорта. Stock равен nil, если колонка не заполнена:
+// тогда у существующего товара остаток не меняется.
+type ImportProductRow struct {
+	ProductID   int
+	SKU         string
+	Name        string
+	Description string
+	Price       Money
+	Stock       *int
+}
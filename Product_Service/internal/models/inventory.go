@@ -0,0 +1,13 @@
+package models
+
+type ReservationStatus string
+
+const (
+	ReservationStatusReserved  ReservationStatus = "reserved"
+	ReservationStatusCommitted ReservationStatus = "committed"
+	ReservationStatusReleased  ReservationStatus = "released"
+)
+
+type UpdateStockRequest struct {
+	Stock int `json:"stock"`
+}
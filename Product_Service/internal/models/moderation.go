@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+type ProductStatus string
+
+const (
+	ProductStatusDraft         ProductStatus = "draft"
+	ProductStatusPendingReview ProductStatus = "pending_review"
+	ProductStatusPublished     ProductStatus = "published"
+	ProductStatusRejected      ProductStatus = "rejected"
+)
+
+type ModerationDecision string
+
+const (
+	DecisionApprove ModerationDecision = "approve"
+	DecisionReject  ModerationDecision = "reject"
+)
+
+type ModerateProductRequest struct {
+	Decision ModerationDecision `json:"decision"`
+	Reason   string             `json:"reason"`
+}
+
+type BannedWord struct {
+	ID   int    `json:"id"`
+	Word string `json:"word"`
+}
+
+type BannedWordRequest struct {
+	Word string `json:"word"`
+}
+
+// NormalizeText приводит текст к словам в нижнем регистре через пробел, отбрасывая знаки препинания.
+func NormalizeText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// MatchBannedWords возвращает запрещенные слова и фразы, которые целиком встречаются в тексте.
+func MatchBannedWords(text string, banned []string) []string {
+	normalized := " " + NormalizeText(text) + " "
+
+	matches := []string{}
+	for _, word := range banned {
+		phrase := NormalizeText(word)
+		if phrase != "" && strings.Contains(normalized, " "+phrase+" ") {
+			matches = append(matches, word)
+		}
+	}
+	return matches
+}
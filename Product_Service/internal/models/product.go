@@ -1,9 +1,46 @@
 package models
 
+import "time"
+
+// Product — товар каталога. EffectivePrice — цена с учетом действующей акции, по ней товар продается.
+// ModerationReason и ModerationFlags (совпавшие запрещенные слова) заполняются при модерации.
+// Locale — язык отданных name и description, DefaultLocale — язык, на котором их заполнил поставщик.
 type Product struct {
-	ID          int    `json:"id"`
+	ID               int              `json:"id"`
+	SKU              string           `json:"sku,omitempty"`
+	Name             string           `json:"name"`
+	Description      string           `json:"description"`
+	Locale           string           `json:"locale"`
+	DefaultLocale    string           `json:"default_locale"`
+	Price            Money            `json:"price"`
+	EffectivePrice   Money            `json:"effective_price"`
+	PriceDisplay     PriceDisplay     `json:"price_display"`
+	Promotion        *Promotion       `json:"promotion,omitempty"`
+	UserID           int              `json:"user_id"`
+	Stock            int              `json:"stock"`
+	Rating           float64          `json:"rating"`
+	ReviewCount      int              `json:"review_count"`
+	CategoryIDs      []int            `json:"category_ids,omitempty"`
+	Images           []ProductImage   `json:"images,omitempty"`
+	Options          []ProductOption  `json:"options,omitempty"`
+	Variants         []ProductVariant `json:"variants,omitempty"`
+	ArchivedAt       *time.Time       `json:"archived_at,omitempty"`
+	Status           ProductStatus    `json:"status"`
+	ModerationReason string           `json:"moderation_reason,omitempty"`
+	ModerationFlags  []string         `json:"moderation_flags,omitempty"`
+}
+
+// IsVisible сообщает, виден ли товар клиентам: он опубликован и не в архиве.
+func (p Product) IsVisible() bool {
+	return p.ArchivedAt == nil && p.Status == ProductStatusPublished
+}
+
+type UpdateProductRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
-	Price       int    `json:"price"`
-	UserID      int    `json:"user_id"`
+	Price       Money  `json:"price"`
+}
+
+type ProductFilter struct {
+	CategoryID int
 }
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent"
+	DiscountFixed   DiscountType = "fixed"
+)
+
+var ErrInvalidPromotion = errors.New("invalid promotion")
+
+// Promotion — акционная цена товара на период [StartsAt, EndsAt). DiscountValue — процент скидки
+// для percent или сумма скидки в минорных единицах валюты товара для fixed.
+type Promotion struct {
+	ID            int          `json:"id"`
+	ProductID     int          `json:"product_id"`
+	DiscountType  DiscountType `json:"discount_type"`
+	DiscountValue int64        `json:"discount_value"`
+	StartsAt      time.Time    `json:"starts_at"`
+	EndsAt        time.Time    `json:"ends_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// Apply возвращает цену со скидкой. Для nil акции цена не меняется. Скидка, после которой
+// от цены ничего не остается (фиксированная скидка не меньше цены), не применяется.
+func (p *Promotion) Apply(price Money) Money {
+	if p == nil {
+		return price
+	}
+
+	discounted := price
+	switch p.DiscountType {
+	case DiscountPercent:
+		discounted.Amount -= price.Amount * p.DiscountValue / 100
+	case DiscountFixed:
+		discounted.Amount -= p.DiscountValue
+	}
+	if discounted.Amount <= 0 {
+		return price
+	}
+	return discounted
+}
+
+type PromotionRequest struct {
+	DiscountType  DiscountType `json:"discount_type"`
+	DiscountValue int64        `json:"discount_value"`
+	StartsAt      *time.Time   `json:"starts_at"`
+	EndsAt        time.Time    `json:"ends_at"`
+}
+
+func (r PromotionRequest) Validate() error {
+	switch r.DiscountType {
+	case DiscountPercent:
+		if r.DiscountValue < 1 || r.DiscountValue > 99 {
+			return errors.New("percent discount must be between 1 and 99")
+		}
+	case DiscountFixed:
+		if r.DiscountValue <= 0 {
+			return errors.New("fixed discount must be positive")
+		}
+	default:
+		return errors.New("discount_type must be percent or fixed")
+	}
+
+	if r.StartsAt != nil && !r.EndsAt.After(*r.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if !r.EndsAt.After(time.Now()) {
+		return errors.New("ends_at must be in the future")
+	}
+	return nil
+}
+
+// ValidateFor проверяет, что фиксированная скидка меньше каждой из цен товара и его вариантов.
+func (r PromotionRequest) ValidateFor(prices []Money) error {
+	if r.DiscountType != DiscountFixed {
+		return nil
+	}
+	for _, price := range prices {
+		if r.DiscountValue >= price.Amount {
+			return fmt.Errorf("fixed discount must be less than the price %s", price)
+		}
+	}
+	return nil
+}
+
+// PriceDisplay — цена для витрины: во время акции Was содержит прежнюю цену, Now — цену со скидкой.
+type PriceDisplay struct {
+	Was             *Money `json:"was,omitempty"`
+	Now             Money  `json:"now"`
+	DiscountPercent int64  `json:"discount_percent,omitempty"`
+}
+
+func NewPriceDisplay(price, effective Money) PriceDisplay {
+	display := PriceDisplay{Now: effective}
+	if effective.Amount < price.Amount {
+		was := price
+		display.Was = &was
+		display.DiscountPercent = (price.Amount - effective.Amount) * 100 / price.Amount
+	}
+	return display
+}
+
+type PriceHistoryEntry struct {
+	Price     Money     `json:"price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Question — вопрос клиента о товаре. Ответ дает поставщик-владелец товара, вопросы и ответы видны всем.
+type Question struct {
+	ID         int        `json:"id"`
+	ProductID  int        `json:"product_id"`
+	ClientID   int        `json:"client_id"`
+	Text       string     `json:"text"`
+	Answer     string     `json:"answer,omitempty"`
+	AnsweredAt *time.Time `json:"answered_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type QuestionRequest struct {
+	Text string `json:"text"`
+}
+
+type AnswerRequest struct {
+	Text string `json:"text"`
+}
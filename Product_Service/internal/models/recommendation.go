@@ -0,0 +1,16 @@
+package models
+
+type RecommendationReason string
+
+const (
+	ReasonBoughtTogether RecommendationReason = "frequently_bought_together"
+	ReasonPopular        RecommendationReason = "popular"
+)
+
+// Recommendation — рекомендованный товар. Score — сколько раз товар покупали вместе с исходным
+// или, для популярных товаров, сколько раз его покупали вообще.
+type Recommendation struct {
+	Product Product              `json:"product"`
+	Score   int                  `json:"score"`
+	Reason  RecommendationReason `json:"reason"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPublished ReviewStatus = "published"
+	ReviewStatusFlagged   ReviewStatus = "flagged"
+	ReviewStatusHidden    ReviewStatus = "hidden"
+)
+
+type Review struct {
+	ID            int          `json:"id"`
+	ProductID     int          `json:"product_id"`
+	ClientID      int          `json:"client_id"`
+	OrderID       int          `json:"order_id"`
+	Rating        int          `json:"rating"`
+	Text          string       `json:"text"`
+	Photos        []string     `json:"photos"`
+	Status        ReviewStatus `json:"status"`
+	FlagCount     int          `json:"flag_count"`
+	SupplierReply string       `json:"supplier_reply,omitempty"`
+	RepliedAt     *time.Time   `json:"replied_at,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type CreateReviewRequest struct {
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+type ReviewReplyRequest struct {
+	Text string `json:"text"`
+}
+
+type FlagReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ModerateReviewRequest struct {
+	Status ReviewStatus `json:"status"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type ProductSearchResult struct {
+	Product
+	Rank                 float64 `json:"rank"`
+	NameHighlight        string  `json:"name_highlight"`
+	DescriptionHighlight string  `json:"description_highlight"`
+}
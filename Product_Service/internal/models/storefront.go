@@ -0,0 +1,27 @@
+package models
+
+type SupplierInfo struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// SupplierStats — агрегаты по всем товарам поставщика. Заказы учитываются по событиям order-events,
+// FulfillmentRate — доля доставленных заказов среди завершенных (доставленных и отмененных).
+type SupplierStats struct {
+	ProductCount    int     `json:"product_count"`
+	Rating          float64 `json:"rating"`
+	ReviewCount     int     `json:"review_count"`
+	OrdersTotal     int     `json:"orders_total"`
+	OrdersDelivered int     `json:"orders_delivered"`
+	OrdersCancelled int     `json:"orders_cancelled"`
+	ItemsSold       int     `json:"items_sold"`
+	FulfillmentRate float64 `json:"fulfillment_rate"`
+}
+
+type Storefront struct {
+	Supplier SupplierInfo  `json:"supplier"`
+	Stats    SupplierStats `json:"stats"`
+	Products []Product     `json:"products"`
+	Limit    int           `json:"limit"`
+	Offset   int           `json:"offset"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const DefaultLocale = "ru"
+
+// SupportedLocales — языки интерфейса, для которых можно хранить перевод товара
+var SupportedLocales = []string{"ru", "en"}
+
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+func IsSupportedLocale(locale string) bool {
+	for _, supported := range SupportedLocales {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+type ProductTranslation struct {
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type TranslationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Localize подставляет в товар перевод, если он есть; иначе остается текст на языке поставщика.
+func (p *Product) Localize(translation *ProductTranslation) {
+	if translation == nil || translation.Locale == p.DefaultLocale {
+		return
+	}
+	p.Name = translation.Name
+	p.Description = translation.Description
+	p.Locale = translation.Locale
+}
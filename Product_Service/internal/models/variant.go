@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrVariantOptionsMismatch = errors.New("variant options do not match product option types")
+
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type ProductVariant struct {
+	ID             int               `json:"id"`
+	ProductID      int               `json:"product_id"`
+	SKU            string            `json:"sku"`
+	Options        map[string]string `json:"options"`
+	Price          *Money            `json:"price,omitempty"`
+	EffectivePrice Money             `json:"effective_price"`
+	PriceDisplay   PriceDisplay      `json:"price_display"`
+	Stock          int               `json:"stock"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+type SetProductOptionsRequest struct {
+	Options []ProductOption `json:"options"`
+}
+
+type VariantRequest struct {
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	Price   *Money            `json:"price"`
+	Stock   int               `json:"stock"`
+}
+
+// ValidateVariantOptions проверяет, что для каждого типа опции товара выбрано ровно одно допустимое значение.
+func ValidateVariantOptions(optionTypes []ProductOption, selected map[string]string) error {
+	if len(selected) != len(optionTypes) {
+		return ErrVariantOptionsMismatch
+	}
+
+	for _, option := range optionTypes {
+		value, ok := selected[option.Name]
+		if !ok {
+			return fmt.Errorf("%w: missing %q", ErrVariantOptionsMismatch, option.Name)
+		}
+
+		allowed := false
+		for _, candidate := range option.Values {
+			if candidate == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%w: %q is not a valid %q", ErrVariantOptionsMismatch, value, option.Name)
+		}
+	}
+	return nil
+}
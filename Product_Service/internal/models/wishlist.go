@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type Wishlist struct {
+	ID        int       `json:"id"`
+	ClientID  int       `json:"client_id"`
+	Name      string    `json:"name"`
+	Products  []Product `json:"products"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WishlistRequest struct {
+	Name string `json:"name"`
+}
+
+type WishlistItemRequest struct {
+	ProductID int `json:"product_id"`
+}
+
+// WishlistWatch — товар из списков желаний клиента вместе с ценой и наличием на момент прошлой проверки.
+type WishlistWatch struct {
+	ClientID    int
+	Product     Product
+	LastPrice   Money
+	LastInStock bool
+	InStock     bool
+}
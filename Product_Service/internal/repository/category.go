@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryCycle    = errors.New("category cannot be moved under itself or its descendant")
+	ErrCategoryInUse    = errors.New("category has subcategories")
+)
+
+func (repo *PGRepo) CreateCategory(request models.CategoryRequest) (int, error) {
+	var id int
+	err := repo.pool.QueryRow(context.Background(), `INSERT INTO categories (name, parent_id) VALUES ($1, $2) RETURNING id`, request.Name, request.ParentID).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (repo *PGRepo) UpdateCategory(id int, request models.CategoryRequest) error {
+	if request.ParentID != nil {
+		// Новый родитель не должен находиться внутри перемещаемого поддерева
+		var isDescendant bool
+		err := repo.pool.QueryRow(context.Background(), `
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM categories WHERE id = $1
+				UNION ALL
+				SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+			)
+			SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)`, id, *request.ParentID).Scan(&isDescendant)
+		if err != nil {
+			return err
+		}
+		if isDescendant {
+			return ErrCategoryCycle
+		}
+	}
+
+	tag, err := repo.pool.Exec(context.Background(), `UPDATE categories SET name = $1, parent_id = $2 WHERE id = $3`, request.Name, request.ParentID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (repo *PGRepo) DeleteCategory(id int) error {
+	var hasChildren bool
+	err := repo.pool.QueryRow(context.Background(), `SELECT EXISTS (SELECT 1 FROM categories WHERE parent_id = $1)`, id).Scan(&hasChildren)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return ErrCategoryInUse
+	}
+
+	tag, err := repo.pool.Exec(context.Background(), `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// GetAllCategories возвращает плоский список категорий с количеством товаров:
+// ProductCount считает только прямые привязки, TotalProductCount учитывает все подкатегории.
+func (repo *PGRepo) GetAllCategories() ([]*models.Category, error) {
+	var categories []*models.Category
+	rows, err := repo.pool.Query(context.Background(), `
+		WITH RECURSIVE tree AS (
+			SELECT id AS root_id, id FROM categories
+			UNION ALL
+			SELECT t.root_id, c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT c.id, c.name, c.parent_id,
+			(SELECT COUNT(*) FROM product_categories pc JOIN products p ON p.id = pc.product_id
+				WHERE pc.category_id = c.id AND `+visibleProduct+`),
+			(SELECT COUNT(DISTINCT pc.product_id) FROM tree t JOIN product_categories pc ON pc.category_id = t.id
+				JOIN products p ON p.id = pc.product_id WHERE t.root_id = c.id AND `+visibleProduct+`)
+		FROM categories c
+		ORDER BY c.name, c.id`)
+	if err != nil {
+		return categories, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category models.Category
+		err := rows.Scan(&category.ID, &category.Name, &category.ParentID, &category.ProductCount, &category.TotalProductCount)
+		if err != nil {
+			return categories, err
+		}
+		categories = append(categories, &category)
+	}
+	return categories, rows.Err()
+}
+
+func (repo *PGRepo) SetProductCategories(productID int, categoryIDs []int) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM product_categories WHERE product_id = $1`, productID); err != nil {
+		return err
+	}
+
+	if err := setProductCategories(tx, productID, categoryIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func setProductCategories(tx pgx.Tx, productID int, categoryIDs []int) error {
+	for _, categoryID := range categoryIDs {
+		_, err := tx.Exec(context.Background(), `INSERT INTO product_categories (product_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, productID, categoryID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
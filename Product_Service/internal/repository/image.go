@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const maxImagesPerProduct = 10
+
+var (
+	ErrImageNotFound      = errors.New("image not found")
+	ErrImageOrderMismatch = errors.New("image_ids must list every image of the product exactly once")
+	ErrTooManyImages      = errors.New("too many images for product")
+)
+
+const imageColumns = `id, product_id, storage_key, thumbnail_key, content_type, size_bytes, width, height, position, created_at`
+
+// CreateProductImage добавляет изображение в конец галереи. Опубликованный товар с новым изображением
+// снова уходит на модерацию.
+func (repo *PGRepo) CreateProductImage(image models.ProductImage) (models.ProductImage, error) {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return image, err
+	}
+	defer tx.Rollback(context.Background())
+
+	// UPDATE блокирует товар, чтобы параллельные загрузки не получили одинаковую позицию
+	if _, err := tx.Exec(context.Background(), `UPDATE products SET `+resubmitIfChanged(`TRUE`)+` WHERE id = $1`, image.ProductID); err != nil {
+		return image, err
+	}
+
+	var count int
+	err = tx.QueryRow(context.Background(), `SELECT COUNT(*), COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $1`, image.ProductID).Scan(&count, &image.Position)
+	if err != nil {
+		return image, err
+	}
+	if count >= maxImagesPerProduct {
+		return image, ErrTooManyImages
+	}
+
+	err = tx.QueryRow(context.Background(), `
+		INSERT INTO product_images (product_id, storage_key, thumbnail_key, content_type, size_bytes, width, height, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`,
+		image.ProductID, image.StorageKey, image.ThumbnailKey, image.ContentType, image.SizeBytes, image.Width, image.Height, image.Position).Scan(&image.ID, &image.CreatedAt)
+	if err != nil {
+		return image, err
+	}
+
+	return image, tx.Commit(context.Background())
+}
+
+func (repo *PGRepo) GetProductImages(productID int) ([]models.ProductImage, error) {
+	images, err := repo.GetImagesForProducts([]int{productID})
+	return images[productID], err
+}
+
+// GetImagesForProducts загружает изображения сразу для списка товаров, чтобы не делать запрос на каждый товар.
+func (repo *PGRepo) GetImagesForProducts(productIDs []int) (map[int][]models.ProductImage, error) {
+	images := map[int][]models.ProductImage{}
+	if len(productIDs) == 0 {
+		return images, nil
+	}
+
+	rows, err := repo.pool.Query(context.Background(), `SELECT `+imageColumns+` FROM product_images WHERE product_id = ANY($1) ORDER BY product_id, position, id`, productIDs)
+	if err != nil {
+		return images, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var image models.ProductImage
+		err := rows.Scan(&image.ID, &image.ProductID, &image.StorageKey, &image.ThumbnailKey, &image.ContentType,
+			&image.SizeBytes, &image.Width, &image.Height, &image.Position, &image.CreatedAt)
+		if err != nil {
+			return images, err
+		}
+		images[image.ProductID] = append(images[image.ProductID], image)
+	}
+	return images, rows.Err()
+}
+
+func (repo *PGRepo) DeleteProductImage(productID, imageID int) (models.ProductImage, error) {
+	var image models.ProductImage
+	err := repo.pool.QueryRow(context.Background(), `DELETE FROM product_images WHERE id = $1 AND product_id = $2 RETURNING `+imageColumns, imageID, productID).Scan(
+		&image.ID, &image.ProductID, &image.StorageKey, &image.ThumbnailKey, &image.ContentType,
+		&image.SizeBytes, &image.Width, &image.Height, &image.Position, &image.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return image, ErrImageNotFound
+	}
+	return image, err
+}
+
+func (repo *PGRepo) ReorderProductImages(productID int, imageIDs []int) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	var count, matched int
+	err = tx.QueryRow(context.Background(),
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE id = ANY($2)) FROM product_images WHERE product_id = $1`,
+		productID, imageIDs).Scan(&count, &matched)
+	if err != nil {
+		return err
+	}
+
+	unique := map[int]bool{}
+	for _, id := range imageIDs {
+		unique[id] = true
+	}
+	if count != len(imageIDs) || matched != len(imageIDs) || len(unique) != len(imageIDs) {
+		return ErrImageOrderMismatch
+	}
+
+	for position, imageID := range imageIDs {
+		_, err := tx.Exec(context.Background(), `UPDATE product_images SET position = $1 WHERE id = $2 AND product_id = $3`, position, imageID, productID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
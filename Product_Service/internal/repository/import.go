@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrImportJobNotFound = errors.New("import job not found")
+	ErrDuplicateSKU      = errors.New("sku is already used by another product")
+)
+
+func (repo *PGRepo) CreateImportJob(supplierID int, filename, format string) (models.ImportJob, error) {
+	return scanImportJob(repo.pool.QueryRow(context.Background(),
+		`INSERT INTO import_jobs (supplier_id, filename, format) VALUES ($1, $2, $3) RETURNING `+importJobColumns,
+		supplierID, filename, format))
+}
+
+const importJobColumns = `id, supplier_id, status, filename, format, total_rows, processed_rows,
+	created_count, updated_count, error_count, errors, created_at, finished_at`
+
+func scanImportJob(row pgx.Row) (models.ImportJob, error) {
+	var job models.ImportJob
+	var rowErrors []byte
+	err := row.Scan(&job.ID, &job.SupplierID, &job.Status, &job.Filename, &job.Format, &job.TotalRows, &job.ProcessedRows,
+		&job.CreatedCount, &job.UpdatedCount, &job.ErrorCount, &rowErrors, &job.CreatedAt, &job.FinishedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return job, ErrImportJobNotFound
+	}
+	if err != nil {
+		return job, err
+	}
+	err = json.Unmarshal(rowErrors, &job.Errors)
+	return job, err
+}
+
+func (repo *PGRepo) GetImportJob(id int) (models.ImportJob, error) {
+	return scanImportJob(repo.pool.QueryRow(context.Background(), `SELECT `+importJobColumns+` FROM import_jobs WHERE id = $1`, id))
+}
+
+// SaveImportProgress сохраняет счетчики и ошибки задания. Статус completed и failed завершают задание.
+func (repo *PGRepo) SaveImportProgress(job models.ImportJob) error {
+	rowErrors, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.pool.Exec(context.Background(),
+		`UPDATE import_jobs SET status = $1, total_rows = $2, processed_rows = $3, created_count = $4, updated_count = $5,
+			error_count = $6, errors = $7::jsonb,
+			finished_at = CASE WHEN $1 IN ('completed', 'failed') THEN CURRENT_TIMESTAMP END
+		WHERE id = $8`,
+		job.Status, job.TotalRows, job.ProcessedRows, job.CreatedCount, job.UpdatedCount, job.ErrorCount, string(rowErrors), job.ID)
+	return err
+}
+
+// FailUnfinishedImportJobs завершает с ошибкой задания, которые остались незавершенными после остановки сервиса.
+// Строки файла хранятся только в памяти, поэтому продолжить такие задания нельзя. Возвращает число заданий.
+func (repo *PGRepo) FailUnfinishedImportJobs(message string) (int64, error) {
+	rowError, err := json.Marshal(models.ImportRowError{Message: message})
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := repo.pool.Exec(context.Background(),
+		`UPDATE import_jobs SET status = $1, error_count = error_count + 1,
+			errors = errors || jsonb_build_array($2::jsonb), finished_at = CURRENT_TIMESTAMP
+		WHERE status IN ($3, $4)`,
+		models.ImportJobFailed, string(rowError), models.ImportJobPending, models.ImportJobProcessing)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// UpsertImportedProduct создает или обновляет товар поставщика из строки импорта.
+// Товар ищется по id, если он указан, иначе по артикулу. Возвращает id товара, признак создания
+// и прежнюю цену обновленного товара. Опубликованный товар с измененными названием или описанием снова уходит на модерацию.
+func (repo *PGRepo) UpsertImportedProduct(supplierID int, row models.ImportProductRow) (int, bool, *models.Money, error) {
+	var (
+		id       int
+		oldPrice models.Money
+		err      error
+	)
+
+	if row.ProductID != 0 {
+		err = repo.pool.QueryRow(context.Background(),
+			`WITH old AS (SELECT price_minor, currency FROM products WHERE id = $1 AND user_id = $2)
+			UPDATE products SET sku = $3, name = $4, description = $5, price_minor = $6, currency = $7,
+				stock = COALESCE($8::int, stock),
+				`+resubmitIfChanged(`(products.name, products.description) IS DISTINCT FROM ($4, $5)`)+`
+			WHERE id = $1 AND user_id = $2
+			RETURNING id, (SELECT price_minor FROM old), (SELECT currency FROM old)`,
+			row.ProductID, supplierID, row.SKU, row.Name, row.Description, row.Price.Amount, row.Price.Currency, row.Stock,
+		).Scan(&id, &oldPrice.Amount, &oldPrice.Currency)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil, ErrProductNotFound
+		}
+		if isUniqueViolation(err) {
+			return 0, false, nil, ErrDuplicateSKU
+		}
+		if err != nil {
+			return 0, false, nil, err
+		}
+		return id, false, &oldPrice, nil
+	}
+
+	// CTE видит таблицу до вставки, поэтому old пуст, если товар с таким артикулом только что создан
+	var oldAmount *int64
+	var oldCurrency *string
+	err = repo.pool.QueryRow(context.Background(),
+		`WITH old AS (SELECT price_minor, currency FROM products WHERE user_id = $1 AND sku = $2)
+		INSERT INTO products (user_id, sku, name, description, price_minor, currency, stock, status, submitted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::int, 0), 'pending_review', NOW())
+		ON CONFLICT (user_id, sku) WHERE sku IS NOT NULL DO UPDATE SET
+			name = EXCLUDED.name, description = EXCLUDED.description, price_minor = EXCLUDED.price_minor,
+			currency = EXCLUDED.currency, stock = COALESCE($7::int, products.stock),
+			`+resubmitIfChanged(`(products.name, products.description) IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.description)`)+`
+		RETURNING id, (SELECT price_minor FROM old), (SELECT currency FROM old)`,
+		supplierID, row.SKU, row.Name, row.Description, row.Price.Amount, row.Price.Currency, row.Stock,
+	).Scan(&id, &oldAmount, &oldCurrency)
+	if err != nil {
+		return 0, false, nil, err
+	}
+
+	if oldAmount == nil {
+		return id, true, nil, nil
+	}
+	return id, false, &models.Money{Amount: *oldAmount, Currency: *oldCurrency}, nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrProductHasOrders = errors.New("product is referenced by orders and can only be archived")
+)
+
+func (repo *PGRepo) UpdateProductStock(productID int, stock int) error {
+	tag, err := repo.pool.Exec(context.Background(), `UPDATE products SET stock = $1 WHERE id = $2`, stock, productID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+// ReserveStock списывает под заказ все его позиции (товар или вариант, если VariantID не 0):
+// либо резервируются все позиции, либо ни одной. Повторная резервация того же заказа ничего
+// не меняет, поэтому событие order_created можно безопасно обработать дважды. Возвращает false,
+// если какой-то позиции не хватает, ее нет в каталоге или товар скрыт (в архиве или не прошел модерацию),
+// и текущие остатки по всем позициям.
+func (repo *PGRepo) ReserveStock(orderID int, items []models.OrderItem) (bool, []models.StockItem, error) {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return false, nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	var existing int
+	var active bool
+	err = tx.QueryRow(context.Background(),
+		`SELECT COUNT(*), COALESCE(bool_and(status <> $2), false) FROM stock_reservations WHERE order_id = $1`,
+		orderID, models.ReservationStatusReleased).Scan(&existing, &active)
+	if err != nil {
+		return false, nil, err
+	}
+	if existing > 0 {
+		stock, err := currentStockItems(tx, items)
+		return active, stock, err
+	}
+
+	reserved := true
+	for _, item := range items {
+		var tag pgconn.CommandTag
+		if item.VariantID != 0 {
+			tag, err = tx.Exec(context.Background(),
+				`UPDATE product_variants SET stock = stock - $1 WHERE id = $2 AND product_id = $3 AND stock >= $1
+					AND EXISTS (SELECT 1 FROM products p WHERE p.id = $3 AND `+visibleProduct+`)`,
+				item.Quantity, item.VariantID, item.ProductID)
+		} else {
+			tag, err = tx.Exec(context.Background(),
+				`UPDATE products p SET stock = stock - $1 WHERE p.id = $2 AND p.stock >= $1 AND `+visibleProduct,
+				item.Quantity, item.ProductID)
+		}
+		if err != nil {
+			return false, nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			reserved = false
+		}
+	}
+
+	// Частичное списание откатывается вместе с транзакцией, остатки читаем уже без него
+	if !reserved {
+		if err := tx.Rollback(context.Background()); err != nil {
+			return false, nil, err
+		}
+		stock, err := currentStockItems(repo.pool, items)
+		return false, stock, err
+	}
+
+	for _, item := range items {
+		_, err = tx.Exec(context.Background(),
+			`INSERT INTO stock_reservations (order_id, product_id, variant_id, quantity, status) VALUES ($1, $2, $3, $4, $5)`,
+			orderID, item.ProductID, item.VariantID, item.Quantity, models.ReservationStatusReserved)
+		if err != nil {
+			return false, nil, err
+		}
+	}
+
+	stock, err := currentStockItems(tx, items)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return false, nil, err
+	}
+	return true, stock, nil
+}
+
+// ReleaseStock возвращает на склад все активные резервы заказа.
+func (repo *PGRepo) ReleaseStock(orderID int) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	rows, err := tx.Query(context.Background(),
+		`UPDATE stock_reservations SET status = $1, updated_at = NOW() WHERE order_id = $2 AND status IN ($3, $4) RETURNING product_id, variant_id, quantity`,
+		models.ReservationStatusReleased, orderID, models.ReservationStatusReserved, models.ReservationStatusCommitted)
+	if err != nil {
+		return err
+	}
+
+	type item struct{ productID, variantID, quantity int }
+	var released []item
+	for rows.Next() {
+		var it item
+		if err := rows.Scan(&it.productID, &it.variantID, &it.quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		released = append(released, it)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, it := range released {
+		if it.variantID != 0 {
+			_, err = tx.Exec(context.Background(), `UPDATE product_variants SET stock = stock + $1 WHERE id = $2`, it.quantity, it.variantID)
+		} else {
+			_, err = tx.Exec(context.Background(), `UPDATE products SET stock = stock + $1 WHERE id = $2`, it.quantity, it.productID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// CommitReservation помечает резервы оплаченного заказа как окончательно проданные.
+func (repo *PGRepo) CommitReservation(orderID int) error {
+	_, err := repo.pool.Exec(context.Background(),
+		`UPDATE stock_reservations SET status = $1, updated_at = NOW() WHERE order_id = $2 AND status = $3`,
+		models.ReservationStatusCommitted, orderID, models.ReservationStatusReserved)
+	return err
+}
+
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+func currentStockItems(q queryRower, items []models.OrderItem) ([]models.StockItem, error) {
+	stock := make([]models.StockItem, 0, len(items))
+	for _, item := range items {
+		available, err := currentStock(q, item.ProductID, item.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		stock = append(stock, models.StockItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity, Available: available})
+	}
+	return stock, nil
+}
+
+// currentStock возвращает остаток товара или варианта; для несуществующих и скрытых позиций остаток 0.
+func currentStock(q queryRower, productID, variantID int) (int, error) {
+	var available int
+	var err error
+	if variantID != 0 {
+		err = q.QueryRow(context.Background(),
+			`SELECT v.stock FROM product_variants v JOIN products p ON p.id = v.product_id WHERE v.id = $1 AND v.product_id = $2 AND `+visibleProduct,
+			variantID, productID).Scan(&available)
+	} else {
+		err = q.QueryRow(context.Background(), `SELECT p.stock FROM products p WHERE p.id = $1 AND `+visibleProduct, productID).Scan(&available)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return available, err
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalidProductStatus = errors.New("product is not in a status that allows this action")
+	ErrBannedWordExists     = errors.New("banned word already exists")
+	ErrBannedWordNotFound   = errors.New("banned word not found")
+)
+
+// SubmitProduct отправляет черновик или отклоненный товар на модерацию.
+func (repo *PGRepo) SubmitProduct(id int, flags []string) error {
+	tag, err := repo.pool.Exec(context.Background(), `
+		UPDATE products SET status = 'pending_review', moderation_flags = COALESCE($2::text[], '{}'),
+			moderation_reason = NULL, submitted_at = NOW()
+		WHERE id = $1 AND status IN ('draft', 'rejected')`, id, flags)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrInvalidProductStatus
+	}
+	return nil
+}
+
+// resubmitIfChanged возвращает присваивания для UPDATE products, которые снимают опубликованный товар
+// с публикации и возвращают его в очередь модерации, если выполнено условие changed.
+func resubmitIfChanged(changed string) string {
+	return `status = CASE WHEN products.status = 'published' AND ` + changed + ` THEN 'pending_review' ELSE products.status END,
+		submitted_at = CASE WHEN products.status = 'published' AND ` + changed + ` THEN NOW() ELSE products.submitted_at END`
+}
+
+// SetModerationFlags сохраняет совпадения с запрещенными словами после изменения текста товара.
+// Опубликованный товар с совпадениями снимается с публикации и снова попадает в очередь модерации.
+func (repo *PGRepo) SetModerationFlags(id int, flags []string) error {
+	_, err := repo.pool.Exec(context.Background(), `
+		UPDATE products SET moderation_flags = COALESCE($2::text[], '{}'),
+			status = CASE WHEN status = 'published' AND cardinality($2::text[]) > 0 THEN 'pending_review' ELSE status END,
+			submitted_at = CASE WHEN status = 'published' AND cardinality($2::text[]) > 0 THEN NOW() ELSE submitted_at END
+		WHERE id = $1`, id, flags)
+	return err
+}
+
+// GetModerationQueue возвращает товары в статусе status; товары с совпадениями запрещенных слов идут первыми.
+func (repo *PGRepo) GetModerationQueue(status models.ProductStatus) ([]models.Product, error) {
+	return repo.queryProducts(`SELECT `+productColumns+` FROM products p
+		WHERE p.status = $1
+		ORDER BY cardinality(p.moderation_flags) > 0 DESC, p.submitted_at, p.id`, status)
+}
+
+// ModerateProduct принимает решение по товару на модерации.
+func (repo *PGRepo) ModerateProduct(id, adminID int, status models.ProductStatus, reason string) error {
+	tag, err := repo.pool.Exec(context.Background(), `
+		UPDATE products SET status = $2, moderation_reason = NULLIF($3, ''), moderated_at = NOW(), moderated_by = $4,
+			moderation_flags = CASE WHEN $2 = 'published' THEN '{}' ELSE moderation_flags END
+		WHERE id = $1 AND status = 'pending_review'`, id, status, reason, adminID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrInvalidProductStatus
+	}
+	return nil
+}
+
+func (repo *PGRepo) GetBannedWords() ([]models.BannedWord, error) {
+	words := []models.BannedWord{}
+	rows, err := repo.pool.Query(context.Background(), `SELECT id, word FROM banned_words ORDER BY word`)
+	if err != nil {
+		return words, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var word models.BannedWord
+		if err := rows.Scan(&word.ID, &word.Word); err != nil {
+			return words, err
+		}
+		words = append(words, word)
+	}
+	return words, rows.Err()
+}
+
+func (repo *PGRepo) CreateBannedWord(word string) (models.BannedWord, error) {
+	bannedWord := models.BannedWord{Word: word}
+	err := repo.pool.QueryRow(context.Background(), `INSERT INTO banned_words (word) VALUES ($1) RETURNING id`, word).Scan(&bannedWord.ID)
+	if isUniqueViolation(err) {
+		return bannedWord, ErrBannedWordExists
+	}
+	return bannedWord, err
+}
+
+func (repo *PGRepo) DeleteBannedWord(id int) error {
+	tag, err := repo.pool.Exec(context.Background(), `DELETE FROM banned_words WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBannedWordNotFound
+	}
+	return nil
+}
+
+// FindBannedWords проверяет текст по текущему списку запрещенных слов.
+func (repo *PGRepo) FindBannedWords(text string) ([]string, error) {
+	bannedWords, err := repo.GetBannedWords()
+	if err != nil {
+		return nil, err
+	}
+
+	words := make([]string, 0, len(bannedWords))
+	for _, word := range bannedWords {
+		words = append(words, word.Word)
+	}
+	return models.MatchBannedWords(text, words), nil
+}
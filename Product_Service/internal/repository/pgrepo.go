@@ -16,5 +16,336 @@ func NewPGRepo(addrStr string) (*PGRepo, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if err := createProductsSchema(pool); err != nil {
+		return nil, err
+	}
+
 	return &PGRepo{mu: &sync.Mutex{}, pool: pool}, nil
 }
+
+func createProductsSchema(pool *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS products (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		price_minor BIGINT NOT NULL,
+		currency CHAR(3) NOT NULL DEFAULT 'RUB',
+		user_id INTEGER NOT NULL
+	);
+
+	-- Старые цены хранились целыми рублями в колонке price, переводим их в копейки
+	DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'products' AND column_name = 'price') THEN
+			ALTER TABLE products ADD COLUMN IF NOT EXISTS price_minor BIGINT;
+			UPDATE products SET price_minor = price::BIGINT * 100;
+			ALTER TABLE products ALTER COLUMN price_minor SET NOT NULL;
+			ALTER TABLE products DROP COLUMN price;
+		END IF;
+	END $$;
+
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(description, '')), 'B')
+	) STORED;
+
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0);
+
+	CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN(search_vector);
+	CREATE INDEX IF NOT EXISTS idx_products_user_id ON products(user_id);
+
+	CREATE TABLE IF NOT EXISTS categories (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+	CREATE TABLE IF NOT EXISTS product_categories (
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+		PRIMARY KEY (product_id, category_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_product_categories_category_id ON product_categories(category_id);
+
+	CREATE TABLE IF NOT EXISTS product_images (
+		id SERIAL PRIMARY KEY,
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		storage_key TEXT NOT NULL,
+		thumbnail_key TEXT NOT NULL,
+		content_type VARCHAR(50) NOT NULL,
+		size_bytes BIGINT NOT NULL,
+		width INTEGER NOT NULL,
+		height INTEGER NOT NULL,
+		position INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_product_images_product_id ON product_images(product_id, position);
+
+	CREATE TABLE IF NOT EXISTS stock_reservations (
+		order_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		quantity INTEGER NOT NULL CHECK (quantity > 0),
+		status VARCHAR(20) NOT NULL DEFAULT 'reserved',
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (order_id, product_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_stock_reservations_status ON stock_reservations(status);
+
+	CREATE TABLE IF NOT EXISTS product_options (
+		id SERIAL PRIMARY KEY,
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		name TEXT NOT NULL,
+		position INTEGER NOT NULL DEFAULT 0,
+		option_values TEXT[] NOT NULL DEFAULT '{}',
+		UNIQUE (product_id, name)
+	);
+
+	CREATE TABLE IF NOT EXISTS product_variants (
+		id SERIAL PRIMARY KEY,
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		sku TEXT NOT NULL UNIQUE,
+		options JSONB NOT NULL DEFAULT '{}',
+		price_minor BIGINT,
+		stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_options ON product_variants(product_id, options);
+
+	-- Резерв может относиться к конкретному варианту товара, 0 означает товар без вариантов
+	ALTER TABLE stock_reservations ADD COLUMN IF NOT EXISTS variant_id INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE stock_reservations DROP CONSTRAINT IF EXISTS stock_reservations_pkey;
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_reservations_item ON stock_reservations(order_id, product_id, variant_id);
+
+	CREATE TABLE IF NOT EXISTS verified_purchases (
+		order_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL,
+		client_id INTEGER NOT NULL,
+		delivered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (order_id, product_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_verified_purchases_client ON verified_purchases(client_id, product_id);
+
+	CREATE TABLE IF NOT EXISTS reviews (
+		id SERIAL PRIMARY KEY,
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		client_id INTEGER NOT NULL,
+		order_id INTEGER NOT NULL,
+		rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+		text TEXT NOT NULL DEFAULT '',
+		status VARCHAR(20) NOT NULL DEFAULT 'published',
+		flag_count INTEGER NOT NULL DEFAULT 0,
+		supplier_reply TEXT,
+		replied_at TIMESTAMP,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (product_id, client_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_reviews_product_status ON reviews(product_id, status);
+
+	CREATE TABLE IF NOT EXISTS review_photos (
+		id SERIAL PRIMARY KEY,
+		review_id INTEGER NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+		storage_key TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS review_flags (
+		review_id INTEGER NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (review_id, user_id)
+	);
+
+	-- Артикул поставщика, по нему массовый импорт находит существующие товары
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS sku TEXT;
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_products_supplier_sku ON products(user_id, sku) WHERE sku IS NOT NULL;
+
+	CREATE TABLE IF NOT EXISTS import_jobs (
+		id SERIAL PRIMARY KEY,
+		supplier_id INTEGER NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		filename TEXT NOT NULL DEFAULT '',
+		format VARCHAR(10) NOT NULL,
+		total_rows INTEGER NOT NULL DEFAULT 0,
+		processed_rows INTEGER NOT NULL DEFAULT 0,
+		created_count INTEGER NOT NULL DEFAULT 0,
+		updated_count INTEGER NOT NULL DEFAULT 0,
+		error_count INTEGER NOT NULL DEFAULT 0,
+		errors JSONB NOT NULL DEFAULT '[]',
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		finished_at TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_import_jobs_supplier_id ON import_jobs(supplier_id);
+
+	CREATE TABLE IF NOT EXISTS price_history (
+		id SERIAL PRIMARY KEY,
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		price_minor BIGINT NOT NULL,
+		currency CHAR(3) NOT NULL,
+		changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_price_history_product_id ON price_history(product_id, changed_at);
+
+	-- История пишется триггером, чтобы ее не обходили ни ручное изменение, ни импорт
+	CREATE OR REPLACE FUNCTION record_price_history() RETURNS trigger AS $fn$
+	BEGIN
+		IF TG_OP = 'UPDATE' AND NEW.price_minor = OLD.price_minor AND NEW.currency = OLD.currency THEN
+			RETURN NEW;
+		END IF;
+		INSERT INTO price_history (product_id, price_minor, currency) VALUES (NEW.id, NEW.price_minor, NEW.currency);
+		RETURN NEW;
+	END;
+	$fn$ LANGUAGE plpgsql;
+
+	DROP TRIGGER IF EXISTS trg_products_price_history ON products;
+	CREATE TRIGGER trg_products_price_history AFTER INSERT OR UPDATE OF price_minor, currency ON products
+		FOR EACH ROW EXECUTE FUNCTION record_price_history();
+
+	INSERT INTO price_history (product_id, price_minor, currency)
+	SELECT p.id, p.price_minor, p.currency FROM products p
+	WHERE NOT EXISTS (SELECT 1 FROM price_history ph WHERE ph.product_id = p.id);
+
+	CREATE TABLE IF NOT EXISTS promotions (
+		id SERIAL PRIMARY KEY,
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		discount_type VARCHAR(10) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+		discount_value BIGINT NOT NULL CHECK (discount_value > 0),
+		starts_at TIMESTAMPTZ NOT NULL,
+		ends_at TIMESTAMPTZ NOT NULL CHECK (ends_at > starts_at),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_promotions_product_period ON promotions(product_id, starts_at, ends_at);
+
+	-- Архивный товар скрыт из каталога и недоступен для заказа, но строка остается для истории заказов
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+	-- Заказы, в которых встречался товар; пока они есть, товар можно только архивировать
+	CREATE TABLE IF NOT EXISTS product_orders (
+		product_id INTEGER NOT NULL,
+		order_id INTEGER NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (product_id, order_id)
+	);
+
+	INSERT INTO product_orders (product_id, order_id)
+	SELECT product_id, order_id FROM stock_reservations
+	UNION
+	SELECT product_id, order_id FROM verified_purchases
+	ON CONFLICT DO NOTHING;
+
+	-- Заказы, созданные до учета резервов, в product_orders не попали. У товаров, существовавших
+	-- до учета, история заказов неизвестна (NULL): их можно только архивировать. Новые товары учитываются полностью.
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS order_history_known BOOLEAN;
+	ALTER TABLE products ALTER COLUMN order_history_known SET DEFAULT TRUE;
+
+	CREATE TABLE IF NOT EXISTS wishlists (
+		id SERIAL PRIMARY KEY,
+		client_id INTEGER NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (client_id, name)
+	);
+
+	-- last_* — цена и наличие товара на момент последней проверки, от них считаются снижение цены и поступление
+	CREATE TABLE IF NOT EXISTS wishlist_items (
+		wishlist_id INTEGER NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		last_price_minor BIGINT NOT NULL,
+		last_currency CHAR(3) NOT NULL,
+		last_in_stock BOOLEAN NOT NULL,
+		added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (wishlist_id, product_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_wishlist_items_product_id ON wishlist_items(product_id);
+
+	-- Покупки клиентов из order_created: по ним считаются пары товаров, купленных вместе, и популярность
+	CREATE TABLE IF NOT EXISTS purchase_history (
+		order_id INTEGER NOT NULL,
+		product_id INTEGER NOT NULL,
+		client_id INTEGER NOT NULL,
+		purchased_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (order_id, product_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_purchase_history_client ON purchase_history(client_id, purchased_at);
+	CREATE INDEX IF NOT EXISTS idx_purchase_history_product ON purchase_history(product_id);
+
+	-- Уже существующие товары считаются опубликованными, новые создаются черновиками
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+	ALTER TABLE products ALTER COLUMN status SET DEFAULT 'draft';
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS moderation_flags TEXT[] NOT NULL DEFAULT '{}';
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMPTZ;
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMPTZ;
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS moderated_by INTEGER;
+
+	CREATE INDEX IF NOT EXISTS idx_products_status ON products(status);
+
+	CREATE TABLE IF NOT EXISTS banned_words (
+		id SERIAL PRIMARY KEY,
+		word TEXT NOT NULL UNIQUE,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Язык, на котором поставщик заполнил name и description; на него откатываемся, если перевода нет
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS default_locale VARCHAR(5) NOT NULL DEFAULT 'ru';
+
+	CREATE TABLE IF NOT EXISTS product_translations (
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		locale VARCHAR(5) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('russian', name), 'A') ||
+			setweight(to_tsvector('english', name), 'A') ||
+			setweight(to_tsvector('russian', description), 'B') ||
+			setweight(to_tsvector('english', description), 'B')
+		) STORED,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (product_id, locale)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_product_translations_search_vector ON product_translations USING GIN(search_vector);
+
+	CREATE TABLE IF NOT EXISTS product_questions (
+		id SERIAL PRIMARY KEY,
+		product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+		client_id INTEGER NOT NULL,
+		text TEXT NOT NULL,
+		answer TEXT,
+		answered_at TIMESTAMP,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_product_questions_product ON product_questions(product_id, created_at DESC);
+
+	CREATE TABLE IF NOT EXISTS co_purchases (
+		product_id INTEGER NOT NULL,
+		related_id INTEGER NOT NULL,
+		count INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (product_id, related_id)
+	);
+	`
+
+	_, err := pool.Exec(context.Background(), query)
+	return err
+}
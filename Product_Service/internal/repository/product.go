@@ -3,60 +3,159 @@ package repository
 import (
 	"Product_Service/internal/models"
 	"context"
+
+	"github.com/jackc/pgx/v4"
 )
 
-func (repo *PGRepo) CreateProduct(product models.Product) (int, error) {
-	err := repo.pool.QueryRow(context.Background(), `INSERT INTO products (name, description, price, user_id) VALUES ($1, $2, $3, $4) RETURNING id`, product.Name, product.Description, product.Price, product.UserID).Scan(&product.ID)
-	if err != nil {
-		return 0, err
-	}
-	return product.ID, nil
-}
+var productColumns = `p.id, p.name, p.description, p.price_minor, p.currency, p.user_id, p.stock, COALESCE(p.sku, ''), p.archived_at,
+	p.status, COALESCE(p.moderation_reason, ''), p.moderation_flags, p.default_locale,
+	COALESCE((SELECT array_agg(pc.category_id ORDER BY pc.category_id) FROM product_categories pc WHERE pc.product_id = p.id), '{}'),
+	COALESCE((SELECT ROUND(AVG(r.rating), 2)::float8 FROM reviews r WHERE r.product_id = p.id AND r.status = 'published'), 0),
+	(SELECT COUNT(*) FROM reviews r WHERE r.product_id = p.id AND r.status = 'published'),
+	` + activePromotionColumn(productPriceExpr)
 
-func (repo *PGRepo) GetProductByID(id int) (models.Product, error) {
+// visibleProduct — условие, при котором товар p виден клиентам и доступен для заказа
+const visibleProduct = `p.archived_at IS NULL AND p.status = 'published'`
+
+// scanProduct читает колонки productColumns; extra получает дополнительные колонки, выбранные после них.
+func scanProduct(row pgx.Row, extra ...interface{}) (models.Product, error) {
 	var product models.Product
-	err := repo.pool.QueryRow(context.Background(), `SELECT id, name, description, price FROM products WHERE id=$1`, id).Scan(&product.ID, &product.Name, &product.Description, &product.Price)
-	return product, err
+	var promotion []byte
+	dest := []interface{}{&product.ID, &product.Name, &product.Description, &product.Price.Amount, &product.Price.Currency,
+		&product.UserID, &product.Stock, &product.SKU, &product.ArchivedAt,
+		&product.Status, &product.ModerationReason, &product.ModerationFlags, &product.DefaultLocale,
+		&product.CategoryIDs, &product.Rating, &product.ReviewCount, &promotion}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return product, err
+	}
+
+	product.Locale = product.DefaultLocale
+	product.Promotion, product.EffectivePrice = applyPromotion(promotion, product.Price)
+	product.PriceDisplay = models.NewPriceDisplay(product.Price, product.EffectivePrice)
+	return product, nil
 }
 
-func (repo *PGRepo) GetAllProductsForClient() ([]models.Product, error) {
+func (repo *PGRepo) queryProducts(query string, args ...interface{}) ([]models.Product, error) {
 	var products []models.Product
-	rows, err := repo.pool.Query(context.Background(), `SELECT id, name, description, price FROM products`)
+	rows, err := repo.pool.Query(context.Background(), query, args...)
 	if err != nil {
 		return products, err
 	}
+	defer rows.Close()
+
 	for rows.Next() {
-		var product models.Product
-		err := rows.Scan(&product.ID, &product.Name, &product.Description, &product.Price)
+		product, err := scanProduct(rows)
 		if err != nil {
 			return products, err
 		}
 		products = append(products, product)
 	}
-	return products, nil
+	return products, rows.Err()
 }
 
-func (repo *PGRepo) DeleteProductByID(id int) error {
-	_, err := repo.pool.Exec(context.Background(), `DELETE FROM products WHERE id=$1`, id)
+func (repo *PGRepo) CreateProduct(product models.Product) (int, error) {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(context.Background())
+
+	err = tx.QueryRow(context.Background(),
+		`INSERT INTO products (name, description, price_minor, currency, user_id, stock, sku, status, moderation_flags, submitted_at, default_locale)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, COALESCE($9::text[], '{}'), CASE WHEN $8 = 'pending_review' THEN NOW() END, $10) RETURNING id`,
+		product.Name, product.Description, product.Price.Amount, product.Price.Currency, product.UserID, product.Stock, product.SKU,
+		product.Status, product.ModerationFlags, product.DefaultLocale).Scan(&product.ID)
+	if isUniqueViolation(err) {
+		return 0, ErrDuplicateSKU
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := setProductCategories(tx, product.ID, product.CategoryIDs); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return 0, err
+	}
+	return product.ID, nil
+}
+
+// UpdateProduct меняет текст и цену товара. Опубликованный товар с измененными названием или описанием
+// снова уходит на модерацию, изменение одной цены его с публикации не снимает.
+func (repo *PGRepo) UpdateProduct(id int, request models.UpdateProductRequest) error {
+	tag, err := repo.pool.Exec(context.Background(),
+		`UPDATE products SET name = $1, description = $2, price_minor = $3, currency = $4,
+			`+resubmitIfChanged(`(products.name, products.description) IS DISTINCT FROM ($1, $2)`)+`
+		WHERE id = $5`,
+		request.Name, request.Description, request.Price.Amount, request.Price.Currency, id)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return ErrProductNotFound
+	}
 	return nil
 }
 
-func (repo *PGRepo) GetAllProductsForSupplier(userID int) ([]models.Product, error) {
-	var products []models.Product
-	rows, err := repo.pool.Query(context.Background(), `SELECT id, name, description, price FROM products WHERE user_id=$1`, userID)
+func (repo *PGRepo) GetProductByID(id int) (models.Product, error) {
+	return scanProduct(repo.pool.QueryRow(context.Background(), `SELECT `+productColumns+` FROM products p WHERE p.id=$1`, id))
+}
+
+func (repo *PGRepo) GetAllProductsForClient(filter models.ProductFilter) ([]models.Product, error) {
+	if filter.CategoryID != 0 {
+		return repo.queryProducts(`
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM categories WHERE id = $1
+				UNION ALL
+				SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+			)
+			SELECT `+productColumns+` FROM products p
+			WHERE `+visibleProduct+`
+				AND EXISTS (SELECT 1 FROM product_categories pc JOIN subtree s ON pc.category_id = s.id WHERE pc.product_id = p.id)
+			ORDER BY p.id`, filter.CategoryID)
+	}
+	return repo.queryProducts(`SELECT ` + productColumns + ` FROM products p WHERE ` + visibleProduct + ` ORDER BY p.id`)
+}
+
+// DeleteProductByID удаляет товар навсегда. Товар, который встречался в заказах или история заказов
+// которого неизвестна, удалить нельзя — только архивировать.
+func (repo *PGRepo) DeleteProductByID(id int) error {
+	tag, err := repo.pool.Exec(context.Background(),
+		`DELETE FROM products p WHERE p.id = $1 AND p.order_history_known
+			AND NOT EXISTS (SELECT 1 FROM product_orders po WHERE po.product_id = p.id)`, id)
 	if err != nil {
-		return products, err
+		return err
 	}
-	for rows.Next() {
-		var product models.Product
-		err := rows.Scan(&product.ID, &product.Name, &product.Description, &product.Price)
-		if err != nil {
-			return products, err
-		}
-		products = append(products, product)
+	if tag.RowsAffected() == 0 {
+		return ErrProductHasOrders
 	}
-	return products, nil
+	return nil
+}
+
+// ArchiveProduct скрывает товар из каталога; повторная архивация не меняет дату.
+func (repo *PGRepo) ArchiveProduct(id int) error {
+	_, err := repo.pool.Exec(context.Background(), `UPDATE products SET archived_at = COALESCE(archived_at, NOW()) WHERE id = $1`, id)
+	return err
+}
+
+func (repo *PGRepo) RestoreProduct(id int) error {
+	_, err := repo.pool.Exec(context.Background(), `UPDATE products SET archived_at = NULL WHERE id = $1`, id)
+	return err
+}
+
+// RecordProductOrder запоминает, что товар встречался в заказе.
+func (repo *PGRepo) RecordProductOrder(orderID, productID int) error {
+	_, err := repo.pool.Exec(context.Background(),
+		`INSERT INTO product_orders (product_id, order_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, productID, orderID)
+	return err
+}
+
+func (repo *PGRepo) GetAllProductsForSupplier(userID int) ([]models.Product, error) {
+	return repo.queryProducts(`SELECT `+productColumns+` FROM products p WHERE p.user_id=$1 AND p.archived_at IS NULL ORDER BY p.id`, userID)
+}
+
+func (repo *PGRepo) GetArchivedProductsForSupplier(userID int) ([]models.Product, error) {
+	return repo.queryProducts(`SELECT `+productColumns+` FROM products p WHERE p.user_id=$1 AND p.archived_at IS NOT NULL ORDER BY p.archived_at DESC, p.id`, userID)
 }
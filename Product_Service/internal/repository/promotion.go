@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrPromotionNotFound = errors.New("promotion not found")
+
+// productPriceExpr и variantPriceExpr — цена товара p и варианта v в минорных единицах до скидки
+const (
+	productPriceExpr = `p.price_minor`
+	variantPriceExpr = `COALESCE(v.price_minor, p.price_minor)`
+)
+
+// activePromotionFilter отбирает действующие акции pr товара p для цены price.
+// Фиксированная скидка не меньше этой цены не учитывается.
+func activePromotionFilter(price string) string {
+	return `pr.product_id = p.id AND pr.starts_at <= CURRENT_TIMESTAMP AND pr.ends_at > CURRENT_TIMESTAMP
+		AND (pr.discount_type = 'percent' OR pr.discount_value < ` + price + `)`
+}
+
+// promotionDiscountExpr — размер скидки акции pr с цены price в минорных единицах, как в models.Promotion.Apply
+func promotionDiscountExpr(price string) string {
+	return `CASE WHEN pr.discount_type = 'percent' THEN ` + price + ` * pr.discount_value / 100 ELSE pr.discount_value END`
+}
+
+// activePromotionColumn выбирает действующую акцию товара p для цены price. Если акций несколько,
+// берется та, что дает самую низкую цену.
+func activePromotionColumn(price string) string {
+	return `(SELECT json_build_object('id', pr.id, 'product_id', pr.product_id,
+		'discount_type', pr.discount_type, 'discount_value', pr.discount_value,
+		'starts_at', pr.starts_at, 'ends_at', pr.ends_at, 'created_at', pr.created_at)
+	FROM promotions pr
+	WHERE ` + activePromotionFilter(price) + `
+	ORDER BY ` + promotionDiscountExpr(price) + ` DESC, pr.id
+	LIMIT 1)`
+}
+
+// effectivePriceExpr — цена товара p в минорных единицах с учетом действующей акции
+var effectivePriceExpr = `(` + productPriceExpr + ` - COALESCE((SELECT MAX(` + promotionDiscountExpr(productPriceExpr) + `)
+	FROM promotions pr WHERE ` + activePromotionFilter(productPriceExpr) + `), 0))`
+
+// applyPromotion разбирает колонку activePromotionColumn и возвращает акцию и цену с ее учетом.
+// Если акция не меняет цену, акция не возвращается.
+func applyPromotion(data []byte, price models.Money) (*models.Promotion, models.Money) {
+	if data == nil {
+		return nil, price
+	}
+
+	var promotion models.Promotion
+	if err := json.Unmarshal(data, &promotion); err != nil {
+		log.Printf("Failed to decode promotion: %v", err)
+		return nil, price
+	}
+	effective := promotion.Apply(price)
+	if effective == price {
+		return nil, price
+	}
+	return &promotion, effective
+}
+
+const promotionColumns = `id, product_id, discount_type, discount_value, starts_at, ends_at, created_at`
+
+func scanPromotion(row pgx.Row) (models.Promotion, error) {
+	var promotion models.Promotion
+	err := row.Scan(&promotion.ID, &promotion.ProductID, &promotion.DiscountType, &promotion.DiscountValue,
+		&promotion.StartsAt, &promotion.EndsAt, &promotion.CreatedAt)
+	return promotion, err
+}
+
+func (repo *PGRepo) CreatePromotion(productID int, request models.PromotionRequest) (models.Promotion, error) {
+	startsAt := time.Now()
+	if request.StartsAt != nil {
+		startsAt = *request.StartsAt
+	}
+
+	return scanPromotion(repo.pool.QueryRow(context.Background(),
+		`INSERT INTO promotions (product_id, discount_type, discount_value, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING `+promotionColumns,
+		productID, request.DiscountType, request.DiscountValue, startsAt, request.EndsAt))
+}
+
+// GetProductPromotions возвращает действующие и запланированные акции товара, завершенные не показываются.
+func (repo *PGRepo) GetProductPromotions(productID int) ([]models.Promotion, error) {
+	promotions := []models.Promotion{}
+	rows, err := repo.pool.Query(context.Background(),
+		`SELECT `+promotionColumns+` FROM promotions WHERE product_id = $1 AND ends_at > CURRENT_TIMESTAMP ORDER BY starts_at, id`,
+		productID)
+	if err != nil {
+		return promotions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return promotions, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
+
+func (repo *PGRepo) DeletePromotion(productID, promotionID int) error {
+	tag, err := repo.pool.Exec(context.Background(), `DELETE FROM promotions WHERE id = $1 AND product_id = $2`, promotionID, productID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPromotionNotFound
+	}
+	return nil
+}
+
+func (repo *PGRepo) GetPriceHistory(productID int) ([]models.PriceHistoryEntry, error) {
+	history := []models.PriceHistoryEntry{}
+	rows, err := repo.pool.Query(context.Background(),
+		`SELECT price_minor, currency, changed_at FROM price_history WHERE product_id = $1 ORDER BY changed_at, id`, productID)
+	if err != nil {
+		return history, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.PriceHistoryEntry
+		if err := rows.Scan(&entry.Price.Amount, &entry.Price.Currency, &entry.ChangedAt); err != nil {
+			return history, err
+		}
+		history = append(history, entry)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrQuestionNotFound = errors.New("question not found")
+
+const questionColumns = `q.id, q.product_id, q.client_id, q.text, COALESCE(q.answer, ''), q.answered_at, q.created_at`
+
+func scanQuestion(row pgx.Row) (models.Question, error) {
+	var question models.Question
+	err := row.Scan(&question.ID, &question.ProductID, &question.ClientID, &question.Text, &question.Answer,
+		&question.AnsweredAt, &question.CreatedAt)
+	return question, err
+}
+
+func (repo *PGRepo) queryQuestions(query string, args ...interface{}) ([]models.Question, error) {
+	questions := []models.Question{}
+	rows, err := repo.pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return questions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return questions, err
+		}
+		questions = append(questions, question)
+	}
+	return questions, rows.Err()
+}
+
+func (repo *PGRepo) CreateQuestion(productID, clientID int, text string) (models.Question, error) {
+	return scanQuestion(repo.pool.QueryRow(context.Background(), `
+		INSERT INTO product_questions (product_id, client_id, text) VALUES ($1, $2, $3)
+		RETURNING id, product_id, client_id, text, '', answered_at, created_at`, productID, clientID, text))
+}
+
+func (repo *PGRepo) GetQuestionByID(productID, questionID int) (models.Question, error) {
+	question, err := scanQuestion(repo.pool.QueryRow(context.Background(),
+		`SELECT `+questionColumns+` FROM product_questions q WHERE q.id = $1 AND q.product_id = $2`, questionID, productID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return question, ErrQuestionNotFound
+	}
+	return question, err
+}
+
+// GetProductQuestions возвращает вопросы о товаре: сначала отвеченные, затем новые.
+func (repo *PGRepo) GetProductQuestions(productID, limit, offset int) ([]models.Question, error) {
+	return repo.queryQuestions(`SELECT `+questionColumns+` FROM product_questions q WHERE q.product_id = $1
+		ORDER BY q.answered_at IS NULL, q.created_at DESC, q.id DESC LIMIT $2 OFFSET $3`, productID, limit, offset)
+}
+
+// GetUnansweredQuestionsForSupplier возвращает вопросы без ответа по всем товарам поставщика, старые первыми.
+func (repo *PGRepo) GetUnansweredQuestionsForSupplier(supplierID, limit, offset int) ([]models.Question, error) {
+	return repo.queryQuestions(`SELECT `+questionColumns+` FROM product_questions q JOIN products p ON p.id = q.product_id
+		WHERE p.user_id = $1 AND q.answer IS NULL ORDER BY q.created_at, q.id LIMIT $2 OFFSET $3`, supplierID, limit, offset)
+}
+
+// AnswerQuestion сохраняет ответ поставщика. Повторный ответ заменяет предыдущий;
+// firstAnswer сообщает, что на вопрос ответили впервые.
+func (repo *PGRepo) AnswerQuestion(productID, questionID int, answer string) (bool, error) {
+	var firstAnswer bool
+	err := repo.pool.QueryRow(context.Background(), `
+		UPDATE product_questions q SET answer = $1, answered_at = NOW()
+		FROM (SELECT id, answer IS NULL AS first_answer FROM product_questions WHERE id = $2 AND product_id = $3 FOR UPDATE) old
+		WHERE q.id = old.id
+		RETURNING old.first_answer`, answer, questionID, productID).Scan(&firstAnswer)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrQuestionNotFound
+	}
+	return firstAnswer, err
+}
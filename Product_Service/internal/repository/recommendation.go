@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+)
+
+// Покупки одного клиента в пределах этого окна считаются купленными вместе
+const coPurchaseWindow = "30 days"
+
+// RecordPurchase сохраняет покупку и увеличивает счетчики пар с товарами, которые клиент
+// покупал за последние coPurchaseWindow. Повторное событие того же заказа ничего не меняет.
+func (repo *PGRepo) RecordPurchase(orderID, productID, clientID int) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	tag, err := tx.Exec(context.Background(),
+		`INSERT INTO purchase_history (order_id, product_id, client_id) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		orderID, productID, clientID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(context.Background(), `
+		WITH related AS (
+			SELECT DISTINCT product_id FROM purchase_history
+			WHERE client_id = $1 AND product_id <> $2 AND purchased_at > NOW() - INTERVAL '`+coPurchaseWindow+`'
+		),
+		pairs AS (
+			SELECT $2::int AS product_id, product_id AS related_id FROM related
+			UNION ALL
+			SELECT product_id, $2::int FROM related
+		)
+		INSERT INTO co_purchases (product_id, related_id, count)
+		SELECT product_id, related_id, 1 FROM pairs
+		ON CONFLICT (product_id, related_id) DO UPDATE SET count = co_purchases.count + 1`,
+		clientID, productID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// GetBoughtTogether возвращает активные товары, которые чаще всего покупали вместе с productID.
+func (repo *PGRepo) GetBoughtTogether(productID, limit int) ([]models.Recommendation, error) {
+	return repo.queryRecommendations(models.ReasonBoughtTogether, `
+		SELECT `+productColumns+`, cp.count
+		FROM co_purchases cp JOIN products p ON p.id = cp.related_id
+		WHERE cp.product_id = $1 AND `+visibleProduct+`
+		ORDER BY cp.count DESC, p.id
+		LIMIT $2`, productID, limit)
+}
+
+// GetPopularProducts — запасной вариант для товаров без истории совместных покупок:
+// самые покупаемые активные товары, сначала из тех же категорий, что и productID.
+func (repo *PGRepo) GetPopularProducts(productID int, exclude []int, limit int) ([]models.Recommendation, error) {
+	return repo.queryRecommendations(models.ReasonPopular, `
+		WITH source_categories AS (
+			SELECT category_id FROM product_categories WHERE product_id = $1
+		),
+		popularity AS (
+			SELECT product_id, COUNT(*) AS purchases FROM purchase_history GROUP BY product_id
+		)
+		SELECT `+productColumns+`, COALESCE(pop.purchases, 0)::int
+		FROM products p
+		LEFT JOIN popularity pop ON pop.product_id = p.id
+		WHERE p.id <> $1 AND `+visibleProduct+` AND NOT (p.id = ANY($2))
+		ORDER BY EXISTS (
+				SELECT 1 FROM product_categories pc JOIN source_categories sc ON sc.category_id = pc.category_id
+				WHERE pc.product_id = p.id
+			) DESC,
+			COALESCE(pop.purchases, 0) DESC, p.id
+		LIMIT $3`, productID, exclude, limit)
+}
+
+func (repo *PGRepo) queryRecommendations(reason models.RecommendationReason, query string, args ...interface{}) ([]models.Recommendation, error) {
+	var recommendations []models.Recommendation
+	rows, err := repo.pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return recommendations, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		recommendation := models.Recommendation{Reason: reason}
+		recommendation.Product, err = scanProduct(rows, &recommendation.Score)
+		if err != nil {
+			return recommendations, err
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations, rows.Err()
+}
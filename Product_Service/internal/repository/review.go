@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Отзыв с таким количеством жалоб скрывается до решения модератора
+const reviewFlagThreshold = 3
+
+const maxPhotosPerReview = 5
+
+var (
+	ErrNotVerifiedBuyer    = errors.New("only clients with a delivered order can review this product")
+	ErrReviewExists        = errors.New("you have already reviewed this product")
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrAlreadyFlagged      = errors.New("you have already flagged this review")
+	ErrTooManyReviewPhotos = errors.New("too many photos for review")
+)
+
+const reviewColumns = `r.id, r.product_id, r.client_id, r.order_id, r.rating, r.text, r.status, r.flag_count,
+	COALESCE(r.supplier_reply, ''), r.replied_at, r.created_at,
+	COALESCE((SELECT array_agg(rp.storage_key ORDER BY rp.id) FROM review_photos rp WHERE rp.review_id = r.id), '{}')`
+
+func scanReview(row pgx.Row) (models.Review, error) {
+	var review models.Review
+	err := row.Scan(&review.ID, &review.ProductID, &review.ClientID, &review.OrderID, &review.Rating, &review.Text, &review.Status,
+		&review.FlagCount, &review.SupplierReply, &review.RepliedAt, &review.CreatedAt, &review.Photos)
+	return review, err
+}
+
+func (repo *PGRepo) queryReviews(query string, args ...interface{}) ([]models.Review, error) {
+	reviews := []models.Review{}
+	rows, err := repo.pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return reviews, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return reviews, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+// RecordVerifiedPurchase запоминает доставленный заказ, дающий клиенту право оставить отзыв.
+func (repo *PGRepo) RecordVerifiedPurchase(orderID, productID, clientID int) error {
+	_, err := repo.pool.Exec(context.Background(),
+		`INSERT INTO verified_purchases (order_id, product_id, client_id) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		orderID, productID, clientID)
+	return err
+}
+
+func (repo *PGRepo) CreateReview(productID, clientID int, request models.CreateReviewRequest) (models.Review, error) {
+	var orderID int
+	err := repo.pool.QueryRow(context.Background(),
+		`SELECT order_id FROM verified_purchases WHERE product_id = $1 AND client_id = $2 ORDER BY delivered_at DESC LIMIT 1`,
+		productID, clientID).Scan(&orderID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Review{}, ErrNotVerifiedBuyer
+	}
+	if err != nil {
+		return models.Review{}, err
+	}
+
+	var reviewID int
+	err = repo.pool.QueryRow(context.Background(),
+		`INSERT INTO reviews (product_id, client_id, order_id, rating, text) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		productID, clientID, orderID, request.Rating, request.Text).Scan(&reviewID)
+	if isUniqueViolation(err) {
+		return models.Review{}, ErrReviewExists
+	}
+	if err != nil {
+		return models.Review{}, err
+	}
+
+	return repo.GetReviewByID(productID, reviewID)
+}
+
+func (repo *PGRepo) GetReviewByID(productID, reviewID int) (models.Review, error) {
+	review, err := scanReview(repo.pool.QueryRow(context.Background(),
+		`SELECT `+reviewColumns+` FROM reviews r WHERE r.id = $1 AND r.product_id = $2`, reviewID, productID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return review, ErrReviewNotFound
+	}
+	return review, err
+}
+
+func (repo *PGRepo) GetPublishedReviews(productID, limit, offset int) ([]models.Review, error) {
+	return repo.queryReviews(`SELECT `+reviewColumns+` FROM reviews r WHERE r.product_id = $1 AND r.status = $2 ORDER BY r.created_at DESC, r.id DESC LIMIT $3 OFFSET $4`,
+		productID, models.ReviewStatusPublished, limit, offset)
+}
+
+func (repo *PGRepo) GetFlaggedReviews(limit, offset int) ([]models.Review, error) {
+	return repo.queryReviews(`SELECT `+reviewColumns+` FROM reviews r WHERE r.status = $1 ORDER BY r.flag_count DESC, r.id LIMIT $2 OFFSET $3`,
+		models.ReviewStatusFlagged, limit, offset)
+}
+
+func (repo *PGRepo) AddReviewPhoto(reviewID int, storageKey string) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	var count int
+	if err := tx.QueryRow(context.Background(), `SELECT COUNT(*) FROM review_photos WHERE review_id = $1`, reviewID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= maxPhotosPerReview {
+		return ErrTooManyReviewPhotos
+	}
+
+	if _, err := tx.Exec(context.Background(), `INSERT INTO review_photos (review_id, storage_key) VALUES ($1, $2)`, reviewID, storageKey); err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+func (repo *PGRepo) ReplyToReview(productID, reviewID int, text string) error {
+	tag, err := repo.pool.Exec(context.Background(),
+		`UPDATE reviews SET supplier_reply = $1, replied_at = NOW() WHERE id = $2 AND product_id = $3`,
+		text, reviewID, productID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReviewNotFound
+	}
+	return nil
+}
+
+// FlagReview регистрирует жалобу на отзыв. После reviewFlagThreshold жалоб опубликованный
+// отзыв переходит в очередь модерации и пропадает из каталога.
+func (repo *PGRepo) FlagReview(productID, reviewID, userID int, reason string) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	var exists bool
+	err = tx.QueryRow(context.Background(), `SELECT EXISTS (SELECT 1 FROM reviews WHERE id = $1 AND product_id = $2)`, reviewID, productID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrReviewNotFound
+	}
+
+	_, err = tx.Exec(context.Background(), `INSERT INTO review_flags (review_id, user_id, reason) VALUES ($1, $2, $3)`, reviewID, userID, reason)
+	if isUniqueViolation(err) {
+		return ErrAlreadyFlagged
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(context.Background(), `
+		UPDATE reviews SET flag_count = flag_count + 1,
+			status = CASE WHEN status = $1 AND flag_count + 1 >= $2 THEN $3 ELSE status END
+		WHERE id = $4`,
+		models.ReviewStatusPublished, reviewFlagThreshold, models.ReviewStatusFlagged, reviewID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (repo *PGRepo) ModerateReview(reviewID int, status models.ReviewStatus) error {
+	// Одобренный отзыв снова начинает копить жалобы с нуля
+	tag, err := repo.pool.Exec(context.Background(),
+		`UPDATE reviews SET status = $1, flag_count = CASE WHEN $1 = $2 THEN 0 ELSE flag_count END WHERE id = $3`,
+		status, models.ReviewStatusPublished, reviewID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReviewNotFound
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"strings"
+	"unicode"
+)
+
+// SearchProducts ищет товары по названию и описанию с учетом русской и английской морфологии.
+// Каждое слово запроса ищется по префиксу, чтобы поиск работал по мере набора текста.
+// Совпадение ищется и в переводах товара, а результат и подсветка отдаются на языке locale,
+// если перевод на него есть.
+func (repo *PGRepo) SearchProducts(text, locale string, limit, offset int) ([]models.ProductSearchResult, error) {
+	var results []models.ProductSearchResult
+
+	tsQuery := buildPrefixTSQuery(text)
+	if tsQuery == "" {
+		return results, nil
+	}
+
+	rows, err := repo.pool.Query(context.Background(), `
+		WITH q AS (SELECT to_tsquery('russian', $1) || to_tsquery('english', $1) AS query)
+		SELECT `+productColumns+`,
+			GREATEST(ts_rank_cd(p.search_vector, q.query),
+				COALESCE((SELECT MAX(ts_rank_cd(pt.search_vector, q.query)) FROM product_translations pt WHERE pt.product_id = p.id), 0)) AS rank,
+			ts_headline('russian', COALESCE(t.name, p.name), q.query, 'StartSel=<b>, StopSel=</b>, HighlightAll=true'),
+			ts_headline('russian', COALESCE(t.description, p.description), q.query, 'StartSel=<b>, StopSel=</b>, MaxFragments=2, MaxWords=20, MinWords=5'),
+			COALESCE(t.locale, ''), COALESCE(t.name, ''), COALESCE(t.description, '')
+		FROM products p
+		CROSS JOIN q
+		LEFT JOIN product_translations t ON t.product_id = p.id AND t.locale = $4
+		WHERE (p.search_vector @@ q.query
+			OR EXISTS (SELECT 1 FROM product_translations pt WHERE pt.product_id = p.id AND pt.search_vector @@ q.query))
+			AND `+visibleProduct+`
+		ORDER BY rank DESC, p.id
+		LIMIT $2 OFFSET $3`, tsQuery, limit, offset, locale)
+	if err != nil {
+		return results, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result models.ProductSearchResult
+		var translation models.ProductTranslation
+		result.Product, err = scanProduct(rows, &result.Rank, &result.NameHighlight, &result.DescriptionHighlight,
+			&translation.Locale, &translation.Name, &translation.Description)
+		if err != nil {
+			return results, err
+		}
+		if translation.Locale != "" {
+			result.Product.Localize(&translation)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// buildPrefixTSQuery превращает пользовательский ввод в tsquery вида "слово1:* & слово2:*",
+// отбрасывая служебные символы tsquery, чтобы ввод клиента не ломал синтаксис запроса.
+func buildPrefixTSQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, strings.ToLower(word)+":*")
+	}
+	return strings.Join(terms, " & ")
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+)
+
+// GetSupplierStats считает рейтинг и статистику выполнения заказов по всем товарам поставщика, включая скрытые.
+// Заказ считается отмененным, если все его резервы по товарам поставщика сняты, и не доставленным.
+func (repo *PGRepo) GetSupplierStats(supplierID int) (models.SupplierStats, error) {
+	var stats models.SupplierStats
+	err := repo.pool.QueryRow(context.Background(), `
+		WITH supplier_products AS (
+			SELECT p.id, `+visibleProduct+` AS visible FROM products p WHERE p.user_id = $1
+		),
+		supplier_orders AS (
+			SELECT DISTINCT po.order_id FROM product_orders po JOIN supplier_products sp ON sp.id = po.product_id
+		),
+		delivered AS (
+			SELECT DISTINCT vp.order_id FROM verified_purchases vp JOIN supplier_products sp ON sp.id = vp.product_id
+		),
+		cancelled AS (
+			SELECT sr.order_id FROM stock_reservations sr JOIN supplier_products sp ON sp.id = sr.product_id
+			GROUP BY sr.order_id
+			HAVING bool_and(sr.status = 'released')
+			EXCEPT SELECT order_id FROM delivered
+		)
+		SELECT
+			(SELECT COUNT(*) FROM supplier_products WHERE visible),
+			COALESCE((SELECT ROUND(AVG(r.rating), 2)::float8 FROM reviews r JOIN supplier_products sp ON sp.id = r.product_id WHERE r.status = 'published'), 0),
+			(SELECT COUNT(*) FROM reviews r JOIN supplier_products sp ON sp.id = r.product_id WHERE r.status = 'published'),
+			(SELECT COUNT(*) FROM supplier_orders),
+			(SELECT COUNT(*) FROM delivered),
+			(SELECT COUNT(*) FROM cancelled),
+			COALESCE((SELECT SUM(sr.quantity) FROM stock_reservations sr JOIN supplier_products sp ON sp.id = sr.product_id WHERE sr.status = 'committed'), 0)`,
+		supplierID,
+	).Scan(&stats.ProductCount, &stats.Rating, &stats.ReviewCount, &stats.OrdersTotal, &stats.OrdersDelivered,
+		&stats.OrdersCancelled, &stats.ItemsSold)
+	if err != nil {
+		return stats, err
+	}
+
+	if finished := stats.OrdersDelivered + stats.OrdersCancelled; finished > 0 {
+		stats.FulfillmentRate = float64(stats.OrdersDelivered*100/finished) / 100
+	}
+	return stats, nil
+}
+
+// GetSupplierStorefrontProducts возвращает страницу видимых клиентам товаров поставщика.
+func (repo *PGRepo) GetSupplierStorefrontProducts(supplierID, limit, offset int) ([]models.Product, error) {
+	return repo.queryProducts(`SELECT `+productColumns+` FROM products p
+		WHERE p.user_id = $1 AND `+visibleProduct+` ORDER BY p.id LIMIT $2 OFFSET $3`, supplierID, limit, offset)
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"errors"
+)
+
+var ErrTranslationNotFound = errors.New("translation not found")
+
+func (repo *PGRepo) GetProductTranslations(productID int) ([]models.ProductTranslation, error) {
+	translations := []models.ProductTranslation{}
+	rows, err := repo.pool.Query(context.Background(),
+		`SELECT locale, name, description, updated_at FROM product_translations WHERE product_id = $1 ORDER BY locale`, productID)
+	if err != nil {
+		return translations, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var translation models.ProductTranslation
+		if err := rows.Scan(&translation.Locale, &translation.Name, &translation.Description, &translation.UpdatedAt); err != nil {
+			return translations, err
+		}
+		translations = append(translations, translation)
+	}
+	return translations, rows.Err()
+}
+
+// GetTranslationsForProducts возвращает переводы на locale для нескольких товаров одним запросом.
+func (repo *PGRepo) GetTranslationsForProducts(productIDs []int, locale string) (map[int]models.ProductTranslation, error) {
+	translations := map[int]models.ProductTranslation{}
+	rows, err := repo.pool.Query(context.Background(),
+		`SELECT product_id, locale, name, description, updated_at FROM product_translations WHERE product_id = ANY($1) AND locale = $2`,
+		productIDs, locale)
+	if err != nil {
+		return translations, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var translation models.ProductTranslation
+		if err := rows.Scan(&productID, &translation.Locale, &translation.Name, &translation.Description, &translation.UpdatedAt); err != nil {
+			return translations, err
+		}
+		translations[productID] = translation
+	}
+	return translations, rows.Err()
+}
+
+// SetProductTranslation создает или заменяет перевод. Новый или измененный перевод опубликованного товара
+// еще не проверен модерацией, поэтому товар снимается с публикации и снова уходит на проверку.
+func (repo *PGRepo) SetProductTranslation(productID int, locale string, request models.TranslationRequest) (models.ProductTranslation, error) {
+	translation := models.ProductTranslation{Locale: locale, Name: request.Name, Description: request.Description}
+
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return translation, err
+	}
+	defer tx.Rollback(context.Background())
+
+	var changed bool
+	err = tx.QueryRow(context.Background(), `
+		SELECT NOT EXISTS (SELECT 1 FROM product_translations
+			WHERE product_id = $1 AND locale = $2 AND name = $3 AND description = $4)`,
+		productID, locale, request.Name, request.Description).Scan(&changed)
+	if err != nil {
+		return translation, err
+	}
+
+	err = tx.QueryRow(context.Background(), `
+		INSERT INTO product_translations (product_id, locale, name, description) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (product_id, locale) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = NOW()
+		RETURNING updated_at`, productID, locale, request.Name, request.Description).Scan(&translation.UpdatedAt)
+	if err != nil {
+		return translation, err
+	}
+
+	if changed {
+		_, err = tx.Exec(context.Background(), `UPDATE products SET `+resubmitIfChanged(`TRUE`)+` WHERE id = $1`, productID)
+		if err != nil {
+			return translation, err
+		}
+	}
+
+	return translation, tx.Commit(context.Background())
+}
+
+func (repo *PGRepo) DeleteProductTranslation(productID int, locale string) error {
+	tag, err := repo.pool.Exec(context.Background(),
+		`DELETE FROM product_translations WHERE product_id = $1 AND locale = $2`, productID, locale)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+// GetProductModerationText возвращает весь текст товара для проверки модерацией: основной текст и все переводы.
+func (repo *PGRepo) GetProductModerationText(productID int) (string, error) {
+	var text string
+	err := repo.pool.QueryRow(context.Background(), `
+		SELECT concat_ws(' ', p.name, p.description,
+			(SELECT string_agg(t.name || ' ' || t.description, ' ') FROM product_translations t WHERE t.product_id = p.id))
+		FROM products p WHERE p.id = $1`, productID).Scan(&text)
+	return text, err
+}
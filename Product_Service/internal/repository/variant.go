@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrVariantNotFound  = errors.New("variant not found")
+	ErrDuplicateVariant = errors.New("variant with the same sku or options already exists")
+)
+
+// variantColumns выбирает вариант v товара p; акция подбирается по цене варианта, а не товара
+var variantColumns = `v.id, v.product_id, v.sku, v.options, v.price_minor, p.price_minor, p.currency, v.stock, v.created_at, ` +
+	activePromotionColumn(variantPriceExpr)
+
+func scanVariant(row pgx.Row) (models.ProductVariant, error) {
+	var variant models.ProductVariant
+	var priceOverride *int64
+	var basePrice int64
+	var currency string
+	var promotion []byte
+
+	err := row.Scan(&variant.ID, &variant.ProductID, &variant.SKU, &variant.Options, &priceOverride, &basePrice, &currency, &variant.Stock, &variant.CreatedAt, &promotion)
+	if err != nil {
+		return variant, err
+	}
+
+	price := models.NewMoney(basePrice, currency)
+	if priceOverride != nil {
+		price = models.NewMoney(*priceOverride, currency)
+		variant.Price = &price
+	}
+	_, variant.EffectivePrice = applyPromotion(promotion, price)
+	variant.PriceDisplay = models.NewPriceDisplay(price, variant.EffectivePrice)
+	return variant, nil
+}
+
+func (repo *PGRepo) GetProductOptions(productID int) ([]models.ProductOption, error) {
+	options := []models.ProductOption{}
+	rows, err := repo.pool.Query(context.Background(), `SELECT name, option_values FROM product_options WHERE product_id = $1 ORDER BY position, id`, productID)
+	if err != nil {
+		return options, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var option models.ProductOption
+		if err := rows.Scan(&option.Name, &option.Values); err != nil {
+			return options, err
+		}
+		options = append(options, option)
+	}
+	return options, rows.Err()
+}
+
+func (repo *PGRepo) SetProductOptions(productID int, options []models.ProductOption) error {
+	tx, err := repo.pool.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM product_options WHERE product_id = $1`, productID); err != nil {
+		return err
+	}
+
+	for position, option := range options {
+		_, err := tx.Exec(context.Background(),
+			`INSERT INTO product_options (product_id, name, position, option_values) VALUES ($1, $2, $3, $4)`,
+			productID, option.Name, position, option.Values)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (repo *PGRepo) GetProductVariants(productID int) ([]models.ProductVariant, error) {
+	variants := []models.ProductVariant{}
+	rows, err := repo.pool.Query(context.Background(), `SELECT `+variantColumns+` FROM product_variants v JOIN products p ON p.id = v.product_id WHERE v.product_id = $1 ORDER BY v.id`, productID)
+	if err != nil {
+		return variants, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return variants, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, rows.Err()
+}
+
+func (repo *PGRepo) GetVariantByID(productID, variantID int) (models.ProductVariant, error) {
+	variant, err := scanVariant(repo.pool.QueryRow(context.Background(),
+		`SELECT `+variantColumns+` FROM product_variants v JOIN products p ON p.id = v.product_id WHERE v.id = $1 AND v.product_id = $2`,
+		variantID, productID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return variant, ErrVariantNotFound
+	}
+	return variant, err
+}
+
+func (repo *PGRepo) CreateVariant(productID int, request models.VariantRequest) (int, error) {
+	options, err := json.Marshal(request.Options)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = repo.pool.QueryRow(context.Background(),
+		`INSERT INTO product_variants (product_id, sku, options, price_minor, stock) VALUES ($1, $2, $3::jsonb, $4, $5) RETURNING id`,
+		productID, request.SKU, string(options), variantPriceOverride(request), request.Stock).Scan(&id)
+	if isUniqueViolation(err) {
+		return 0, ErrDuplicateVariant
+	}
+	return id, err
+}
+
+func (repo *PGRepo) UpdateVariant(productID, variantID int, request models.VariantRequest) error {
+	options, err := json.Marshal(request.Options)
+	if err != nil {
+		return err
+	}
+
+	tag, err := repo.pool.Exec(context.Background(),
+		`UPDATE product_variants SET sku = $1, options = $2::jsonb, price_minor = $3, stock = $4 WHERE id = $5 AND product_id = $6`,
+		request.SKU, string(options), variantPriceOverride(request), request.Stock, variantID, productID)
+	if isUniqueViolation(err) {
+		return ErrDuplicateVariant
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrVariantNotFound
+	}
+	return nil
+}
+
+func (repo *PGRepo) DeleteVariant(productID, variantID int) error {
+	tag, err := repo.pool.Exec(context.Background(), `DELETE FROM product_variants WHERE id = $1 AND product_id = $2`, variantID, productID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrVariantNotFound
+	}
+	return nil
+}
+
+func variantPriceOverride(request models.VariantRequest) *int64 {
+	if request.Price == nil {
+		return nil
+	}
+	return &request.Price.Amount
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"Product_Service/internal/models"
+	"context"
+	"errors"
+)
+
+var (
+	ErrWishlistNotFound     = errors.New("wishlist not found")
+	ErrWishlistExists       = errors.New("wishlist with this name already exists")
+	ErrWishlistItemNotFound = errors.New("product is not in the wishlist")
+)
+
+// inStockExpr — есть ли в наличии товар p или хотя бы один его вариант
+const inStockExpr = `(p.stock > 0 OR EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id AND v.stock > 0))`
+
+func (repo *PGRepo) CreateWishlist(clientID int, name string) (models.Wishlist, error) {
+	wishlist := models.Wishlist{ClientID: clientID, Name: name, Products: []models.Product{}}
+	err := repo.pool.QueryRow(context.Background(),
+		`INSERT INTO wishlists (client_id, name) VALUES ($1, $2) RETURNING id, created_at`, clientID, name,
+	).Scan(&wishlist.ID, &wishlist.CreatedAt)
+	if isUniqueViolation(err) {
+		return wishlist, ErrWishlistExists
+	}
+	return wishlist, err
+}
+
+// GetClientWishlists возвращает списки клиента с товарами; скрытые товары в списках не показываются.
+func (repo *PGRepo) GetClientWishlists(clientID int) ([]models.Wishlist, error) {
+	wishlists := []models.Wishlist{}
+	rows, err := repo.pool.Query(context.Background(),
+		`SELECT id, client_id, name, created_at FROM wishlists WHERE client_id = $1 ORDER BY id`, clientID)
+	if err != nil {
+		return wishlists, err
+	}
+	defer rows.Close()
+
+	index := map[int]int{}
+	for rows.Next() {
+		wishlist := models.Wishlist{Products: []models.Product{}}
+		if err := rows.Scan(&wishlist.ID, &wishlist.ClientID, &wishlist.Name, &wishlist.CreatedAt); err != nil {
+			return wishlists, err
+		}
+		index[wishlist.ID] = len(wishlists)
+		wishlists = append(wishlists, wishlist)
+	}
+	if err := rows.Err(); err != nil {
+		return wishlists, err
+	}
+
+	itemRows, err := repo.pool.Query(context.Background(), `
+		SELECT `+productColumns+`, wi.wishlist_id
+		FROM wishlist_items wi
+		JOIN wishlists wl ON wl.id = wi.wishlist_id
+		JOIN products p ON p.id = wi.product_id
+		WHERE wl.client_id = $1 AND `+visibleProduct+`
+		ORDER BY wi.added_at, p.id`, clientID)
+	if err != nil {
+		return wishlists, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var wishlistID int
+		product, err := scanProduct(itemRows, &wishlistID)
+		if err != nil {
+			return wishlists, err
+		}
+		if i, ok := index[wishlistID]; ok {
+			wishlists[i].Products = append(wishlists[i].Products, product)
+		}
+	}
+	return wishlists, itemRows.Err()
+}
+
+func (repo *PGRepo) DeleteWishlist(clientID, wishlistID int) error {
+	tag, err := repo.pool.Exec(context.Background(), `DELETE FROM wishlists WHERE id = $1 AND client_id = $2`, wishlistID, clientID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWishlistNotFound
+	}
+	return nil
+}
+
+// AddWishlistItem добавляет товар в список клиента. Текущие цена и наличие становятся точкой отсчета для уведомлений.
+func (repo *PGRepo) AddWishlistItem(clientID, wishlistID int, product models.Product) error {
+	tag, err := repo.pool.Exec(context.Background(), `
+		INSERT INTO wishlist_items (wishlist_id, product_id, last_price_minor, last_currency, last_in_stock)
+		SELECT wl.id, p.id, $3::bigint, $4::char(3), `+inStockExpr+`
+		FROM wishlists wl, products p
+		WHERE wl.id = $1 AND wl.client_id = $5 AND p.id = $2
+		ON CONFLICT (wishlist_id, product_id) DO NOTHING`,
+		wishlistID, product.ID, product.EffectivePrice.Amount, product.EffectivePrice.Currency, clientID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Товар уже в списке или списка нет
+		var exists bool
+		err := repo.pool.QueryRow(context.Background(),
+			`SELECT EXISTS (SELECT 1 FROM wishlists WHERE id = $1 AND client_id = $2)`, wishlistID, clientID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrWishlistNotFound
+		}
+	}
+	return nil
+}
+
+func (repo *PGRepo) RemoveWishlistItem(clientID, wishlistID, productID int) error {
+	tag, err := repo.pool.Exec(context.Background(), `
+		DELETE FROM wishlist_items wi USING wishlists wl
+		WHERE wl.id = wi.wishlist_id AND wi.wishlist_id = $1 AND wi.product_id = $2 AND wl.client_id = $3`,
+		wishlistID, productID, clientID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWishlistItemNotFound
+	}
+	return nil
+}
+
+// GetWishlistWatches возвращает товары, у которых цена или наличие изменились с прошлой проверки, —
+// по одной записи на пару клиент–товар, даже если товар лежит в нескольких списках клиента.
+func (repo *PGRepo) GetWishlistWatches() ([]models.WishlistWatch, error) {
+	var watches []models.WishlistWatch
+	rows, err := repo.pool.Query(context.Background(), `
+		SELECT DISTINCT ON (wl.client_id, p.id) `+productColumns+`,
+			wl.client_id, wi.last_price_minor, wi.last_currency, wi.last_in_stock, `+inStockExpr+`
+		FROM wishlist_items wi
+		JOIN wishlists wl ON wl.id = wi.wishlist_id
+		JOIN products p ON p.id = wi.product_id
+		WHERE `+visibleProduct+`
+			AND (wi.last_price_minor <> `+effectivePriceExpr+` OR wi.last_currency <> p.currency OR wi.last_in_stock <> `+inStockExpr+`)
+		ORDER BY wl.client_id, p.id, wi.added_at`)
+	if err != nil {
+		return watches, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var watch models.WishlistWatch
+		watch.Product, err = scanProduct(rows, &watch.ClientID, &watch.LastPrice.Amount, &watch.LastPrice.Currency,
+			&watch.LastInStock, &watch.InStock)
+		if err != nil {
+			return watches, err
+		}
+		watches = append(watches, watch)
+	}
+	return watches, rows.Err()
+}
+
+// UpdateWishlistPrice запоминает проверенную цену во всех списках клиента с этим товаром.
+func (repo *PGRepo) UpdateWishlistPrice(clientID, productID int, price models.Money) error {
+	_, err := repo.pool.Exec(context.Background(), `
+		UPDATE wishlist_items wi SET last_price_minor = $3, last_currency = $4
+		FROM wishlists wl
+		WHERE wl.id = wi.wishlist_id AND wl.client_id = $1 AND wi.product_id = $2`,
+		clientID, productID, price.Amount, price.Currency)
+	return err
+}
+
+// UpdateWishlistStock запоминает проверенное наличие во всех списках клиента с этим товаром.
+func (repo *PGRepo) UpdateWishlistStock(clientID, productID int, inStock bool) error {
+	_, err := repo.pool.Exec(context.Background(), `
+		UPDATE wishlist_items wi SET last_in_stock = $3
+		FROM wishlists wl
+		WHERE wl.id = wi.wishlist_id AND wl.client_id = $1 AND wi.product_id = $2`,
+		clientID, productID, inStock)
+	return err
+}
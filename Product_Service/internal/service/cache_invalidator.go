@@ -0,0 +1,35 @@
+package service
+
+import (
+	"Product_Service/internal/cache"
+	"Product_Service/internal/models"
+)
+
+// CacheInvalidator сбрасывает кэш каталога по событиям заказов: резервирование и возврат
+// товара на склад меняют остаток, который виден в карточке и списках.
+// Обработчик нужно регистрировать после InventoryService, чтобы сброс шел после изменения остатка.
+type CacheInvalidator struct {
+	cache cache.Cache
+}
+
+func NewCacheInvalidator(c cache.Cache) *CacheInvalidator {
+	return &CacheInvalidator{cache: c}
+}
+
+func (ci *CacheInvalidator) HandleOrderEvent(event models.OrderEvent) error {
+	switch event.EventType {
+	case "order_created", "order_status_updated", "order_cancelled":
+		for _, item := range event.OrderItems() {
+			cache.InvalidateProduct(ci.cache, item.ProductID)
+		}
+	}
+	return nil
+}
+
+func (ci *CacheInvalidator) HandlePaymentEvent(event models.PaymentEvent) error {
+	// В событии платежа нет товара, поэтому при возврате резерва сбрасываем весь кэш
+	if event.EventType == "payment_completed" && event.Status == "failed" {
+		cache.InvalidateAll(ci.cache)
+	}
+	return nil
+}
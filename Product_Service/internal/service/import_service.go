@@ -0,0 +1,261 @@
+package service
+
+import (
+	"Product_Service/internal/models"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Ошибок в отчете хранится не больше этого числа, остальные только учитываются в error_count
+const maxImportErrors = 500
+
+// Прогресс задания сохраняется после каждой такой пачки строк
+const importProgressBatch = 50
+
+// Одновременно обрабатывается не больше importWorkers заданий, еще importQueueSize ждут в очереди
+const (
+	importWorkers   = 2
+	importQueueSize = 20
+)
+
+var ErrImportQueueFull = errors.New("import queue is full")
+
+// ImportColumns — колонки файла импорта и экспорта каталога в порядке выгрузки
+var ImportColumns = []string{"id", "sku", "name", "description", "price", "currency", "stock"}
+
+type ImportRepository interface {
+	SaveImportProgress(job models.ImportJob) error
+	UpsertImportedProduct(supplierID int, row models.ImportProductRow) (int, bool, *models.Money, error)
+	GetBannedWords() ([]models.BannedWord, error)
+	GetProductModerationText(productID int) (string, error)
+	SetModerationFlags(id int, flags []string) error
+}
+
+// ProductImportedFunc вызывается для каждого сохраненного товара; oldPrice задан только для обновленных товаров.
+type ProductImportedFunc func(productID int, created bool, oldPrice *models.Money)
+
+// ImportService в фоне разбирает загруженный каталог поставщика и сохраняет товары по артикулу.
+type ImportService struct {
+	repo       ImportRepository
+	onImported ProductImportedFunc
+	queue      chan importTask
+}
+
+type importTask struct {
+	job  models.ImportJob
+	rows [][]string
+}
+
+func NewImportService(repo ImportRepository, onImported ProductImportedFunc) *ImportService {
+	is := &ImportService{
+		repo:       repo,
+		onImported: onImported,
+		queue:      make(chan importTask, importQueueSize),
+	}
+	for i := 0; i < importWorkers; i++ {
+		go is.work()
+	}
+	return is
+}
+
+// Start ставит созданное задание в очередь и сразу возвращает управление. Если очередь заполнена,
+// задание помечается неудавшимся и возвращается ErrImportQueueFull.
+func (is *ImportService) Start(job models.ImportJob, rows [][]string) error {
+	select {
+	case is.queue <- importTask{job: job, rows: rows}:
+		return nil
+	default:
+		job.Errors = []models.ImportRowError{}
+		is.fail(job, "too many imports in progress, try again later")
+		return ErrImportQueueFull
+	}
+}
+
+func (is *ImportService) work() {
+	for task := range is.queue {
+		is.run(task.job, task.rows)
+	}
+}
+
+func (is *ImportService) run(job models.ImportJob, rows [][]string) {
+	job.Status = models.ImportJobProcessing
+	job.Errors = []models.ImportRowError{}
+
+	if len(rows) == 0 {
+		is.fail(job, "file is empty")
+		return
+	}
+
+	columns, err := parseImportHeader(rows[0])
+	if err != nil {
+		is.fail(job, err.Error())
+		return
+	}
+
+	bannedWords, err := is.repo.GetBannedWords()
+	if err != nil {
+		is.fail(job, "failed to load moderation rules")
+		return
+	}
+	banned := make([]string, 0, len(bannedWords))
+	for _, word := range bannedWords {
+		banned = append(banned, word.Word)
+	}
+
+	rows = rows[1:]
+	job.TotalRows = len(rows)
+	is.save(job)
+
+	for i, values := range rows {
+		// Номер строки в файле: заголовок — первая строка
+		line := i + 2
+		if !isBlankRow(values) {
+			is.importRow(&job, columns, banned, line, values)
+		}
+
+		job.ProcessedRows++
+		if job.ProcessedRows%importProgressBatch == 0 {
+			is.save(job)
+		}
+	}
+
+	job.Status = models.ImportJobCompleted
+	is.save(job)
+	log.Printf("Import job %d finished: %d created, %d updated, %d errors",
+		job.ID, job.CreatedCount, job.UpdatedCount, job.ErrorCount)
+}
+
+// importRow сохраняет одну строку. Новые товары сразу уходят на модерацию, а опубликованные
+// возвращаются в очередь, если изменились текст или цена либо в тексте нашлись запрещенные слова.
+func (is *ImportService) importRow(job *models.ImportJob, columns map[string]int, banned []string, line int, values []string) {
+	row, err := parseImportRow(columns, values)
+	if err != nil {
+		addImportError(job, line, row.SKU, err.Error())
+		return
+	}
+
+	productID, created, oldPrice, err := is.repo.UpsertImportedProduct(job.SupplierID, row)
+	if err != nil {
+		addImportError(job, line, row.SKU, err.Error())
+		return
+	}
+
+	// Проверяем весь текст товара: у обновленного товара могут быть переводы
+	text, err := is.repo.GetProductModerationText(productID)
+	if err != nil {
+		log.Printf("Failed to get text of product %d: %v", productID, err)
+		text = row.Name + " " + row.Description
+	}
+	flags := models.MatchBannedWords(text, banned)
+	if err := is.repo.SetModerationFlags(productID, flags); err != nil {
+		log.Printf("Failed to update moderation flags for product %d: %v", productID, err)
+	}
+
+	if created {
+		job.CreatedCount++
+	} else {
+		job.UpdatedCount++
+	}
+
+	if is.onImported != nil {
+		is.onImported(productID, created, oldPrice)
+	}
+}
+
+func (is *ImportService) fail(job models.ImportJob, message string) {
+	job.Status = models.ImportJobFailed
+	addImportError(&job, 1, "", message)
+	is.save(job)
+}
+
+func (is *ImportService) save(job models.ImportJob) {
+	if err := is.repo.SaveImportProgress(job); err != nil {
+		log.Printf("Failed to save import job %d: %v", job.ID, err)
+	}
+}
+
+func addImportError(job *models.ImportJob, line int, sku, message string) {
+	job.ErrorCount++
+	if len(job.Errors) < maxImportErrors {
+		job.Errors = append(job.Errors, models.ImportRowError{Row: line, SKU: sku, Message: message})
+	}
+}
+
+func parseImportHeader(header []string) (map[string]int, error) {
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		columns[name] = i
+	}
+
+	for _, required := range []string{"sku", "name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing required column %q", required)
+		}
+	}
+	return columns, nil
+}
+
+func parseImportRow(columns map[string]int, values []string) (models.ImportProductRow, error) {
+	value := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(values) {
+			return ""
+		}
+		return strings.TrimSpace(values[i])
+	}
+
+	row := models.ImportProductRow{
+		SKU:         value("sku"),
+		Name:        value("name"),
+		Description: value("description"),
+	}
+
+	if row.SKU == "" {
+		return row, fmt.Errorf("sku is required")
+	}
+	if row.Name == "" {
+		return row, fmt.Errorf("name is required")
+	}
+
+	if id := value("id"); id != "" {
+		productID, err := strconv.Atoi(id)
+		if err != nil || productID <= 0 {
+			return row, fmt.Errorf("invalid id %q", id)
+		}
+		row.ProductID = productID
+	}
+
+	price, err := models.ParseMoney(value("price"), value("currency"))
+	if err != nil {
+		return row, fmt.Errorf("invalid price: %v", err)
+	}
+	row.Price = price
+
+	if stock := value("stock"); stock != "" {
+		quantity, err := strconv.Atoi(stock)
+		if err != nil || quantity < 0 {
+			return row, fmt.Errorf("invalid stock %q", stock)
+		}
+		row.Stock = &quantity
+	}
+	return row, nil
+}
+
+func isBlankRow(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
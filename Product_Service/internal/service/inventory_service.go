@@ -0,0 +1,120 @@
+package service
+
+import (
+	"Product_Service/internal/kafka"
+	"Product_Service/internal/models"
+	"fmt"
+	"log"
+	"time"
+)
+
+type InventoryRepository interface {
+	RecordProductOrder(orderID, productID int) error
+	ReserveStock(orderID int, items []models.OrderItem) (bool, []models.StockItem, error)
+	ReleaseStock(orderID int) error
+	CommitReservation(orderID int) error
+}
+
+type InventoryService struct {
+	repo     InventoryRepository
+	producer *kafka.Producer
+}
+
+func NewInventoryService(repo InventoryRepository, producer *kafka.Producer) *InventoryService {
+	return &InventoryService{
+		repo:     repo,
+		producer: producer,
+	}
+}
+
+func (is *InventoryService) HandleOrderEvent(event models.OrderEvent) error {
+	switch event.EventType {
+	case "order_created":
+		return is.reserveStock(event)
+	case "order_status_updated":
+		if event.Status == "cancelled" {
+			log.Printf("Order %d cancelled, releasing stock", event.OrderID)
+			return is.repo.ReleaseStock(event.OrderID)
+		}
+	case "order_cancelled":
+		log.Printf("Order %d cancelled (%s), releasing stock", event.OrderID, event.Reason)
+		return is.repo.ReleaseStock(event.OrderID)
+	}
+
+	return nil
+}
+
+func (is *InventoryService) HandlePaymentEvent(event models.PaymentEvent) error {
+	if event.EventType != "payment_completed" {
+		return nil
+	}
+
+	switch event.Status {
+	case "completed":
+		return is.repo.CommitReservation(event.OrderID)
+	case "failed":
+		log.Printf("Payment for order %d failed, releasing stock", event.OrderID)
+		return is.repo.ReleaseStock(event.OrderID)
+	}
+
+	return nil
+}
+
+func (is *InventoryService) reserveStock(event models.OrderEvent) error {
+	items := mergeOrderItems(event.OrderItems())
+
+	// Ссылку на заказ сохраняем до резервации: даже неудавшийся заказ не дает удалить товар навсегда
+	for _, item := range items {
+		if err := is.repo.RecordProductOrder(event.OrderID, item.ProductID); err != nil {
+			return fmt.Errorf("failed to record order %d for product %d: %w", event.OrderID, item.ProductID, err)
+		}
+	}
+
+	reserved, stock, err := is.repo.ReserveStock(event.OrderID, items)
+	if err != nil {
+		return fmt.Errorf("failed to reserve stock for order %d: %w", event.OrderID, err)
+	}
+
+	stockEvent := models.StockEvent{
+		EventType: "stock_reserved",
+		OrderID:   event.OrderID,
+		ClientID:  event.ClientID,
+		Items:     stock,
+		Timestamp: time.Now(),
+	}
+	if !reserved {
+		stockEvent.EventType = "stock_insufficient"
+	}
+
+	log.Printf("Stock for order %d: %s (%d items)", event.OrderID, stockEvent.EventType, len(items))
+
+	if is.producer != nil {
+		if err := is.producer.PublishMessage("order-events", stockEvent); err != nil {
+			log.Printf("Failed to publish %s event: %v", stockEvent.EventType, err)
+		}
+	}
+
+	return nil
+}
+
+// mergeOrderItems объединяет позиции одного товара и варианта; позиция без количества — одна штука.
+func mergeOrderItems(items []models.OrderItem) []models.OrderItem {
+	type itemKey struct{ productID, variantID int }
+	merged := make([]models.OrderItem, 0, len(items))
+	index := make(map[itemKey]int, len(items))
+
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			item.Quantity = 1
+		}
+
+		key := itemKey{item.ProductID, item.VariantID}
+		if i, ok := index[key]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"Product_Service/internal/models"
+)
+
+type RecommendationRepository interface {
+	RecordPurchase(orderID, productID, clientID int) error
+}
+
+// RecommendationService накапливает историю покупок из order_created для рекомендаций «покупают вместе».
+type RecommendationService struct {
+	repo RecommendationRepository
+}
+
+func NewRecommendationService(repo RecommendationRepository) *RecommendationService {
+	return &RecommendationService{repo: repo}
+}
+
+func (rs *RecommendationService) HandleOrderEvent(event models.OrderEvent) error {
+	if event.EventType != "order_created" {
+		return nil
+	}
+
+	// Товары одного заказа попадают в пары друг с другом, как и покупки клиента за окно
+	for _, item := range event.OrderItems() {
+		if err := rs.repo.RecordPurchase(event.OrderID, item.ProductID, event.ClientID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rs *RecommendationService) HandlePaymentEvent(event models.PaymentEvent) error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"Product_Service/internal/models"
+	"log"
+)
+
+type ReviewRepository interface {
+	RecordVerifiedPurchase(orderID, productID, clientID int) error
+}
+
+// ReviewService следит за доставленными заказами, чтобы отзывы могли оставлять только реальные покупатели.
+type ReviewService struct {
+	repo ReviewRepository
+}
+
+func NewReviewService(repo ReviewRepository) *ReviewService {
+	return &ReviewService{repo: repo}
+}
+
+func (rs *ReviewService) HandleOrderEvent(event models.OrderEvent) error {
+	if event.EventType != "order_status_updated" || event.Status != "delivered" {
+		return nil
+	}
+
+	for _, item := range event.OrderItems() {
+		log.Printf("Order %d delivered, client %d can review product %d", event.OrderID, event.ClientID, item.ProductID)
+		if err := rs.repo.RecordVerifiedPurchase(event.OrderID, item.ProductID, event.ClientID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rs *ReviewService) HandlePaymentEvent(event models.PaymentEvent) error {
+	return nil
+}
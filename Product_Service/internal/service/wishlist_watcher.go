@@ -0,0 +1,121 @@
+package service
+
+import (
+	"Product_Service/internal/kafka"
+	"Product_Service/internal/models"
+	"context"
+	"log"
+	"strconv"
+	"time"
+)
+
+const wishlistEventsTopic = "wishlist-events"
+
+type WishlistRepository interface {
+	GetWishlistWatches() ([]models.WishlistWatch, error)
+	UpdateWishlistPrice(clientID, productID int, price models.Money) error
+	UpdateWishlistStock(clientID, productID int, inStock bool) error
+}
+
+// WishlistWatcher периодически сверяет товары из списков желаний с их прошлым состоянием
+// и сообщает клиентам о снижении цены и поступлении в продажу. Проверка по расписанию
+// замечает и изменения, которые не проходят через API: начало акции, возврат резерва на склад.
+type WishlistWatcher struct {
+	repo     WishlistRepository
+	producer *kafka.Producer
+	interval time.Duration
+}
+
+func NewWishlistWatcher(repo WishlistRepository, producer *kafka.Producer, interval time.Duration) *WishlistWatcher {
+	return &WishlistWatcher{
+		repo:     repo,
+		producer: producer,
+		interval: interval,
+	}
+}
+
+func (ww *WishlistWatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(ww.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ww.check(); err != nil {
+				log.Printf("Wishlist check failed: %v", err)
+			}
+		}
+	}
+}
+
+func (ww *WishlistWatcher) check() error {
+	watches, err := ww.repo.GetWishlistWatches()
+	if err != nil {
+		return err
+	}
+
+	// Цена и наличие запоминаются отдельно: если одно из событий не ушло, на следующей проверке
+	// повторится только оно, а уже отправленное не придет клиенту второй раз
+	for _, watch := range watches {
+		ww.checkPrice(watch)
+		ww.checkStock(watch)
+	}
+	return nil
+}
+
+func (ww *WishlistWatcher) checkPrice(watch models.WishlistWatch) {
+	price := watch.Product.EffectivePrice
+	if price == watch.LastPrice {
+		return
+	}
+
+	if price.Currency == watch.LastPrice.Currency && price.Amount < watch.LastPrice.Amount {
+		oldPrice := watch.LastPrice
+		if err := ww.publish("wishlist_price_drop", watch, &oldPrice); err != nil {
+			log.Printf("Failed to publish wishlist_price_drop event: %v", err)
+			return
+		}
+	}
+
+	if err := ww.repo.UpdateWishlistPrice(watch.ClientID, watch.Product.ID, price); err != nil {
+		log.Printf("Failed to update wishlist price for client %d, product %d: %v", watch.ClientID, watch.Product.ID, err)
+	}
+}
+
+func (ww *WishlistWatcher) checkStock(watch models.WishlistWatch) {
+	if watch.InStock == watch.LastInStock {
+		return
+	}
+
+	if watch.InStock {
+		if err := ww.publish("wishlist_back_in_stock", watch, nil); err != nil {
+			log.Printf("Failed to publish wishlist_back_in_stock event: %v", err)
+			return
+		}
+	}
+
+	if err := ww.repo.UpdateWishlistStock(watch.ClientID, watch.Product.ID, watch.InStock); err != nil {
+		log.Printf("Failed to update wishlist stock for client %d, product %d: %v", watch.ClientID, watch.Product.ID, err)
+	}
+}
+
+func (ww *WishlistWatcher) publish(eventType string, watch models.WishlistWatch, oldPrice *models.Money) error {
+	log.Printf("Wishlist: %s for client %d, product %d", eventType, watch.ClientID, watch.Product.ID)
+	if ww.producer == nil {
+		return nil
+	}
+
+	event := models.WishlistEvent{
+		EventType:   eventType,
+		ClientID:    watch.ClientID,
+		ProductID:   watch.Product.ID,
+		ProductName: watch.Product.Name,
+		OldPrice:    oldPrice,
+		Price:       watch.Product.EffectivePrice,
+		Timestamp:   time.Now(),
+	}
+
+	return ww.producer.PublishKeyedMessage(wishlistEventsTopic, strconv.Itoa(watch.ClientID), event)
+}
@@ -0,0 +1,52 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported file format, use csv or xlsx")
+
+// DetectFormat определяет формат по содержимому: XLSX — это zip-архив, все остальное читается как CSV.
+func DetectFormat(data []byte) Format {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return FormatXLSX
+	}
+	return FormatCSV
+}
+
+func Read(format Format, data []byte) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case FormatXLSX:
+		return ReadXLSX(data)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+func Write(format Format, w io.Writer, rows [][]string) error {
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	case FormatXLSX:
+		return WriteXLSX(w, rows)
+	}
+	return ErrUnsupportedFormat
+}
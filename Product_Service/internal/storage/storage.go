@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage хранит бинарные объекты по ключу. Интерфейс повторяет семантику S3
+// (ключ объекта, content-type, публичный URL), чтобы локальное хранилище
+// можно было заменить S3-совместимым без изменений в обработчиках.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
@@ -0,0 +1,53 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+// UserInfo — публичная часть профиля из User Service
+type UserInfo struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// Client обращается к User Service по HTTP.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+func (c *Client) GetUser(id int) (UserInfo, error) {
+	var user UserInfo
+
+	resp, err := c.http.Get(fmt.Sprintf("%s/api/user/%d", c.baseURL, id))
+	if err != nil {
+		return user, fmt.Errorf("user service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return user, ErrUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return user, fmt.Errorf("failed to get user %d: status %d", id, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return user, fmt.Errorf("failed to decode user %d: %w", id, err)
+	}
+	return user, nil
+}